@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/scanner"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var detectorsCmd = &cobra.Command{
+	Use:   "detectors",
+	Short: "Inspect the registered scan detectors",
+}
+
+var detectorsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered detectors and whether they are enabled",
+	Long:  `Lists every registered detector with its scope, resource types, default risk and required IAM permissions. Pass the same flags as scan (e.g. --deep, --s3) to see which detectors that scan would run.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		deep, _ := cmd.Flags().GetBool("deep")
+		s3, _ := cmd.Flags().GetBool("s3")
+
+		scn := scanner.New(nil, deep)
+		scn.S3 = s3
+
+		data := [][]string{
+			{"ID", "Enabled", "Scope", "Resources", "Default Risk", "Permissions"},
+		}
+
+		for _, d := range scn.Detectors {
+			info := d.Info()
+
+			enabled := pterm.FgGray.Sprint("no")
+			if d.Enabled(scn) {
+				enabled = pterm.FgGreen.Sprint("yes")
+			}
+
+			resources := make([]string, 0, len(info.ResourceTypes))
+			for _, r := range info.ResourceTypes {
+				resources = append(resources, string(r))
+			}
+
+			data = append(data, []string{
+				pterm.FgCyan.Sprint(info.ID),
+				enabled,
+				string(info.Scope),
+				strings.Join(resources, ", "),
+				string(info.DefaultRisk),
+				strings.Join(info.Permissions, ", "),
+			})
+		}
+
+		_ = pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(detectorsCmd)
+	detectorsCmd.AddCommand(detectorsListCmd)
+	detectorsListCmd.Flags().Bool("deep", false, "Show detectors enabled by --deep")
+	detectorsListCmd.Flags().Bool("s3", false, "Show detectors enabled by --s3")
+}
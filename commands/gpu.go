@@ -0,0 +1,252 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws"
+	"github.com/K0NGR3SS/ghostweights/internal/config"
+	"github.com/K0NGR3SS/ghostweights/internal/scanner"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var gpuCmd = &cobra.Command{
+	Use:   "gpu",
+	Short: "Report GPU instances, idle GPUs and their monthly cost",
+	Long:  `Lists every GPU and ML accelerator instance (p*, g*, inf*, trn*, dl*), running or stopped, with its launch time, owner tags and on-demand price from a bundled price table. With --deep, GPU utilization is sampled over SSM and instances below 5% are reported as idle.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath, _ := cmd.Flags().GetString("config")
+		cfg, cfgFile, err := config.Resolve(configPath)
+		if err != nil {
+			pterm.Error.Printf("Failed to load config: %v\n", err)
+			os.Exit(1)
+		}
+		if cfgFile != "" {
+			pterm.Info.Printf("Using config file %s\n", cfgFile)
+		}
+
+		opts := resolveScanOptions(cmd, cfg)
+		if !cmd.Flags().Changed("format") && opts.outputFormat != "json" {
+			// The config file's format may be csv, which only scan writes.
+			opts.outputFormat = "table"
+		}
+		if opts.outputFormat != "table" && opts.outputFormat != "json" {
+			pterm.Error.Printf("Invalid format: %s (must be: table or json)\n", opts.outputFormat)
+			os.Exit(1)
+		}
+
+		var regions []string
+		switch {
+		case opts.allRegions || len(opts.includeRegions) > 0 || len(opts.excludeRegions) > 0:
+			discovery, err := discoverRegions(bootstrapRegion(opts), opts.includeRegions, opts.excludeRegions)
+			if err != nil {
+				pterm.Error.Printf("Failed to discover regions: %v\n", err)
+				os.Exit(1)
+			}
+			regions = discovery.Enabled
+		case opts.region != "":
+			regions = []string{opts.region}
+		case len(opts.regions) > 0:
+			regions = opts.regions
+		default:
+			pterm.Error.Println("Specify --region, --all-regions or regions in the config file")
+			os.Exit(1)
+		}
+
+		inventory, errs := inventoryRegions(regions, opts)
+		sortGPUs(inventory)
+
+		if opts.outputFormat == "json" {
+			jsonData, _ := json.MarshalIndent(inventory, "", "  ")
+			fmt.Println(string(jsonData))
+		} else {
+			pterm.Println()
+			printGPUs(inventory, opts.deep)
+		}
+
+		for _, err := range errs {
+			pterm.Error.Println(err)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// inventoryRegions lists GPU instances in regions, at most opts.concurrency
+// regions at a time.
+func inventoryRegions(regions []string, opts scanOptions) ([]scanner.GPUInstance, []error) {
+	multi := pterm.DefaultMultiPrinter
+	spinners := make([]*pterm.SpinnerPrinter, len(regions))
+	for i, region := range regions {
+		spinners[i] = ui.StartLabeledSpinner(&multi, region, "Waiting...")
+	}
+	_, _ = multi.Start()
+
+	results := make([][]scanner.GPUInstance, len(regions))
+	errs := make([]error, len(regions))
+
+	sem := make(chan struct{}, opts.concurrency)
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i], errs[i] = inventoryRegion(region, opts, spinners[i])
+		}(i, region)
+	}
+	wg.Wait()
+	_, _ = multi.Stop()
+
+	var inventory []scanner.GPUInstance
+	var inventoryErrs []error
+	for i := range regions {
+		inventory = append(inventory, results[i]...)
+		if errs[i] != nil {
+			inventoryErrs = append(inventoryErrs, errs[i])
+		}
+	}
+	return inventory, inventoryErrs
+}
+
+func inventoryRegion(region string, opts scanOptions, spinner *pterm.SpinnerPrinter) ([]scanner.GPUInstance, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	ui.UpdateSpinner(spinner, "Connecting to AWS...")
+	awsClient, err := aws.NewClient(ctx, region)
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("[%s] Error initializing AWS client: %v", region, err))
+		return nil, fmt.Errorf("region %s: %w", region, err)
+	}
+
+	scn := scanner.New(awsClient, opts.deep)
+	scn.ExcludeIDs = opts.excludeIDs
+	scn.ExcludeTags = opts.excludeTags
+
+	inventory, err := scn.GPUInventory(ctx, spinner)
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("[%s] GPU inventory failed: %v", region, err))
+		return nil, fmt.Errorf("region %s: %w", region, err)
+	}
+	spinner.Success(fmt.Sprintf("[%s] %d GPU instances", region, len(inventory)))
+	return inventory, nil
+}
+
+// sortGPUs puts idle instances first, then orders by monthly cost (highest
+// first), region and instance ID.
+func sortGPUs(inventory []scanner.GPUInstance) {
+	sort.SliceStable(inventory, func(i, j int) bool {
+		a, b := inventory[i], inventory[j]
+		if a.Idle != b.Idle {
+			return a.Idle
+		}
+		if a.MonthlyCost != b.MonthlyCost {
+			return a.MonthlyCost > b.MonthlyCost
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.InstanceID < b.InstanceID
+	})
+}
+
+func printGPUs(inventory []scanner.GPUInstance, deep bool) {
+	if len(inventory) == 0 {
+		pterm.Success.Println("No GPU instances found.")
+		return
+	}
+
+	data := [][]string{
+		{"Region", "Instance ID", "Name", "Type", "GPUs", "State", "Launched", "Owner", "Utilization", "Monthly Cost"},
+	}
+
+	var running, stopped, idle int
+	var runningCost, idleCost float64
+	for _, g := range inventory {
+		gpus := "-"
+		if g.GPUs > 0 {
+			gpus = fmt.Sprintf("%dx %s", g.GPUs, g.Accelerator)
+		}
+		launched := "-"
+		if !g.LaunchTime.IsZero() {
+			launched = g.LaunchTime.UTC().Format("2006-01-02")
+		}
+		util := "-"
+		if g.Utilization != nil {
+			util = fmt.Sprintf("%.1f%%", *g.Utilization)
+			if g.Idle {
+				util = pterm.FgRed.Sprint(util + " idle")
+			}
+		}
+		cost := "-"
+		if g.MonthlyCost > 0 {
+			cost = fmt.Sprintf("$%.0f", g.MonthlyCost)
+		} else if g.HourlyPrice == 0 {
+			cost = "unknown"
+		}
+
+		data = append(data, []string{
+			g.Region,
+			g.InstanceID,
+			g.Name,
+			pterm.FgCyan.Sprint(g.InstanceType),
+			gpus,
+			g.State,
+			launched,
+			g.Owner,
+			util,
+			cost,
+		})
+
+		if g.State == "stopped" {
+			stopped++
+		} else {
+			running++
+			runningCost += g.MonthlyCost
+		}
+		if g.Idle {
+			idle++
+			idleCost += g.MonthlyCost
+		}
+	}
+
+	_ = pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+
+	summary := []string{
+		fmt.Sprintf("GPU Instances: %d (%d running, %d stopped)", len(inventory), running, stopped),
+		fmt.Sprintf("Running Cost: $%.0f/month", runningCost),
+	}
+	if deep {
+		summary = append(summary, fmt.Sprintf("Idle GPUs: %d ($%.0f/month)", idle, idleCost))
+	} else {
+		summary = append(summary, "Idle GPUs: run with --deep to sample utilization")
+	}
+	pterm.Println()
+	pterm.DefaultBox.WithTitle("GPU Summary").Println(strings.Join(summary, "\n"))
+}
+
+func init() {
+	rootCmd.AddCommand(gpuCmd)
+	gpuCmd.Flags().StringP("region", "r", "", "AWS Region to inventory (e.g. eu-west-1)")
+	gpuCmd.Flags().Bool("all-regions", false, "Inventory all regions enabled for the account")
+	gpuCmd.Flags().StringSlice("include-regions", []string{}, "Only inventory enabled regions matching these patterns (e.g. eu-*,us-*)")
+	gpuCmd.Flags().StringSlice("exclude-regions", []string{}, "Skip enabled regions matching these patterns")
+	gpuCmd.Flags().Int("concurrency", 4, "Number of regions to inventory in parallel")
+	gpuCmd.Flags().Bool("deep", false, "Sample GPU utilization over SSM to find idle GPUs")
+	gpuCmd.Flags().String("format", "table", "Output format: table or json")
+	gpuCmd.Flags().StringSlice("exclude-ids", []string{}, "Instance IDs to exclude")
+	gpuCmd.Flags().StringSlice("exclude-tags", []string{}, "Instance tags to exclude (key or key=value, wildcards allowed)")
+	gpuCmd.Flags().StringP("config", "c", "", "Path to config file (default: ./ghostweights.yaml, then $XDG_CONFIG_HOME/ghostweights/config.yaml)")
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/K0NGR3SS/ghostweights/internal/config"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/notifications"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	envSlackWebhook = "GHOSTWEIGHTS_SLACK_WEBHOOK"
+	envSlackChannel = "GHOSTWEIGHTS_SLACK_CHANNEL"
+)
+
+// notifyOptions controls where and when scan results are posted.
+type notifyOptions struct {
+	target     string
+	webhookURL string
+	channel    string
+	minRisk    models.RiskLevel
+	clean      bool
+}
+
+// resolveNotifyOptions merges the notify flags with the config file. The
+// webhook and channel come from the environment first, then the config.
+func resolveNotifyOptions(cmd *cobra.Command, cfg *config.Config) (notifyOptions, error) {
+	flags := cmd.Flags()
+
+	var opts notifyOptions
+	opts.target, _ = flags.GetString("notify")
+	if opts.target == "" {
+		return opts, nil
+	}
+	if opts.target != "slack" {
+		return opts, fmt.Errorf("unsupported notify target: %s (must be: slack)", opts.target)
+	}
+
+	minRisk, _ := flags.GetString("notify-min-risk")
+	if !flags.Changed("notify-min-risk") && cfg.Slack.MinRisk != "" {
+		minRisk = cfg.Slack.MinRisk
+	}
+	opts.minRisk = parseRiskLevel(minRisk)
+
+	opts.clean, _ = flags.GetBool("notify-clean")
+	if !flags.Changed("notify-clean") && cfg.Slack.NotifyClean {
+		opts.clean = true
+	}
+
+	opts.webhookURL = os.Getenv(envSlackWebhook)
+	if opts.webhookURL == "" {
+		opts.webhookURL = cfg.Slack.WebhookURL
+	}
+	if opts.webhookURL == "" {
+		return opts, fmt.Errorf("slack webhook not configured (set %s or slack.webhook_url)", envSlackWebhook)
+	}
+
+	opts.channel = os.Getenv(envSlackChannel)
+	if opts.channel == "" {
+		opts.channel = cfg.Slack.Channel
+	}
+
+	return opts, nil
+}
+
+// notify posts findings when at least one is at or above the threshold, or
+// a clean report when there are none and clean reports are enabled. A scan
+// with errors is never reported clean; it gets an incomplete report instead.
+func notify(opts notifyOptions, findings []models.Finding, scanErrs []error) error {
+	if opts.target == "" {
+		return nil
+	}
+
+	if len(findings) == 0 {
+		if !opts.clean {
+			pterm.Info.Println("No findings, skipping Slack notification")
+			return nil
+		}
+		if len(scanErrs) > 0 {
+			notifier := notifications.NewSlackNotifier(opts.webhookURL, opts.channel)
+			if err := notifier.SendIncompleteReport(scanErrs); err != nil {
+				return err
+			}
+			pterm.Warning.Println("Scan incomplete report sent to Slack")
+			return nil
+		}
+	} else if len(filterByRisk(findings, opts.minRisk)) == 0 {
+		pterm.Info.Printf("No findings at or above %s, skipping Slack notification\n", opts.minRisk)
+		return nil
+	}
+
+	notifier := notifications.NewSlackNotifier(opts.webhookURL, opts.channel)
+	if err := notifier.SendFindings(findings); err != nil {
+		return err
+	}
+
+	pterm.Success.Println("Results sent to Slack")
+	return nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+)
+
+func TestNotifyCleanWithScanErrors(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	opts := notifyOptions{target: "slack", webhookURL: srv.URL, minRisk: models.RiskHigh, clean: true}
+	scanErrs := []error{errors.New("region eu-west-1: AccessDenied")}
+
+	if err := notify(opts, nil, scanErrs); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if !strings.Contains(body, "Scan Incomplete") || !strings.Contains(body, "eu-west-1") {
+		t.Errorf("body = %s, want an incomplete report naming the failure", body)
+	}
+	if strings.Contains(body, "looks clean") {
+		t.Errorf("body = %s, want no clean report", body)
+	}
+}
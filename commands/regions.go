@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/probe"
+	"github.com/K0NGR3SS/ghostweights/internal/scanner"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/pterm/pterm"
+)
+
+// discoverRegions lists the regions enabled for the account, calling
+// DescribeRegions from bootstrap.
+func discoverRegions(bootstrap string, include, exclude []string) (*scanner.RegionDiscovery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	awsClient, err := aws.NewClient(ctx, bootstrap)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanner.DiscoverRegions(ctx, awsClient.EC2, include, exclude)
+}
+
+// bootstrapRegion picks the region used for account-wide calls such as
+// DescribeRegions and the S3 scan.
+func bootstrapRegion(opts scanOptions) string {
+	if opts.region != "" {
+		return opts.region
+	}
+	if len(opts.regions) > 0 {
+		return opts.regions[0]
+	}
+	return "us-east-1"
+}
+
+// scanRegions scans regions in parallel, at most opts.concurrency at a time,
+// with one progress line per region. Findings are merged and sorted so the
+// report doesn't depend on which region finished first. A region that fails
+// is returned as an error rather than dropped; a region where only some
+// detectors failed keeps its findings and is returned as an error too.
+func scanRegions(regions []string, opts scanOptions) ([]models.Finding, []error) {
+	pterm.Println()
+	pterm.DefaultSection.Printf("Phase 1-2: Discovery & Analysis (%d regions, %d at a time)", len(regions), opts.concurrency)
+
+	multi := pterm.DefaultMultiPrinter
+	spinners := make([]*pterm.SpinnerPrinter, len(regions))
+	for i, region := range regions {
+		spinners[i] = ui.StartLabeledSpinner(&multi, region, "Waiting...")
+	}
+	_, _ = multi.Start()
+
+	results := make([][]models.Finding, len(regions))
+	errs := make([]error, len(regions))
+
+	sem := make(chan struct{}, opts.concurrency)
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i], errs[i] = scanRegion(region, opts, spinners[i])
+		}(i, region)
+	}
+	wg.Wait()
+	_, _ = multi.Stop()
+
+	var findings []models.Finding
+	var scanErrs []error
+	for i := range regions {
+		findings = append(findings, results[i]...)
+		if errs[i] != nil {
+			scanErrs = append(scanErrs, errs[i])
+		}
+	}
+	sortFindings(findings)
+
+	return findings, scanErrs
+}
+
+func scanRegion(region string, opts scanOptions, spinner *pterm.SpinnerPrinter) ([]models.Finding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	ui.UpdateSpinner(spinner, "Connecting to AWS...")
+	awsClient, err := aws.NewClient(ctx, region)
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("[%s] Error initializing AWS client: %v", region, err))
+		return nil, fmt.Errorf("region %s: %w", region, err)
+	}
+
+	ui.UpdateSpinner(spinner, "Hunting for Shadow AI artifacts...")
+	scn := scanner.New(awsClient, opts.deep)
+	scn.Ports = opts.ports
+	scn.ExcludeIDs = opts.excludeIDs
+	scn.ExcludeTags = opts.excludeTags
+	scn.Kubeconfig = opts.kubeconfig
+	if opts.probe {
+		scn.Prober = probe.New(opts.probeTimeout)
+	}
+
+	findings, err := scn.Scan(ctx, spinner)
+	var partial *scanner.PartialError
+	switch {
+	case errors.As(err, &partial):
+		// Keep what the working detectors found, but don't let the region
+		// pass as clean.
+		spinner.Warning(fmt.Sprintf("[%s] Scan incomplete (%d findings): %v", region, len(findings), partial.Err))
+		return findings, fmt.Errorf("region %s: %w", region, err)
+	case err != nil:
+		spinner.Fail(fmt.Sprintf("[%s] Scan failed: %v", region, err))
+		return nil, fmt.Errorf("region %s: %w", region, err)
+	}
+	spinner.Success(fmt.Sprintf("[%s] Scan Complete (%d findings)", region, len(findings)))
+
+	return findings, nil
+}
+
+// sortFindings orders findings by risk (highest first), then region,
+// resource, service and port.
+func sortFindings(findings []models.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Risk.Rank() != b.Risk.Rank() {
+			return a.Risk.Rank() > b.Risk.Rank()
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.InstanceID != b.InstanceID {
+			return a.InstanceID < b.InstanceID
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Port < b.Port
+	})
+}
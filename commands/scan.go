@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/K0NGR3SS/ghostweights/internal/aws"
+	"github.com/K0NGR3SS/ghostweights/internal/config"
 	"github.com/K0NGR3SS/ghostweights/internal/models"
 	"github.com/K0NGR3SS/ghostweights/internal/scanner"
 	"github.com/K0NGR3SS/ghostweights/internal/ui"
@@ -17,38 +18,61 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var validRegions = []string{
-	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
-	"eu-west-1", "eu-west-2", "eu-west-3", "eu-central-1", "eu-north-1", "eu-south-1",
-	"ap-south-1", "ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
-	"ap-southeast-1", "ap-southeast-2", "ap-east-1",
-	"ca-central-1", "sa-east-1", "af-south-1", "me-south-1",
-}
-
 var scanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Start the Shadow AI hunt",
 	Long:  `Scans the target AWS region for EC2 instances exposing AI/ML ports (Ollama, Ray, Streamlit).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		region, _ := cmd.Flags().GetString("region")
-		deep, _ := cmd.Flags().GetBool("deep")
-		outputFormat, _ := cmd.Flags().GetString("format")
-		outputFile, _ := cmd.Flags().GetString("output")
-		minRisk, _ := cmd.Flags().GetString("min-risk")
-		allRegions, _ := cmd.Flags().GetBool("all-regions")
-		excludeIDs, _ := cmd.Flags().GetStringSlice("exclude-ids")
-
-		if outputFormat != "table" && outputFormat != "json" && outputFormat != "csv" {
-			pterm.Error.Printf("Invalid format: %s (must be: table, json, or csv)\n", outputFormat)
+		configPath, _ := cmd.Flags().GetString("config")
+		cfg, cfgFile, err := config.Resolve(configPath)
+		if err != nil {
+			pterm.Error.Printf("Failed to load config: %v\n", err)
+			os.Exit(1)
+		}
+		if cfgFile != "" {
+			pterm.Info.Printf("Using config file %s\n", cfgFile)
+		}
+
+		opts := resolveScanOptions(cmd, cfg)
+		region := opts.region
+
+		notifyOpts, err := resolveNotifyOptions(cmd, cfg)
+		if err != nil {
+			pterm.Error.Println(err)
+			os.Exit(1)
+		}
+
+		if opts.outputFormat != "table" && opts.outputFormat != "json" && opts.outputFormat != "csv" {
+			pterm.Error.Printf("Invalid format: %s (must be: table, json, or csv)\n", opts.outputFormat)
 			os.Exit(1)
 		}
 
-		minRiskLevel := parseRiskLevel(minRisk)
+		minRiskLevel := parseRiskLevel(opts.minRisk)
 
 		var regionsToScan []string
-		if allRegions {
-			regionsToScan = validRegions
-			pterm.Info.Println("Scanning ALL AWS regions (this may take a while)...")
+		if opts.allRegions || len(opts.includeRegions) > 0 || len(opts.excludeRegions) > 0 {
+			discovery, err := discoverRegions(bootstrapRegion(opts), opts.includeRegions, opts.excludeRegions)
+			if err != nil {
+				pterm.Error.Printf("Failed to discover regions: %v\n", err)
+				os.Exit(1)
+			}
+			if len(discovery.NotOptedIn) > 0 {
+				pterm.Warning.Printf("Skipping %d regions not opted in: %s\n",
+					len(discovery.NotOptedIn), strings.Join(discovery.NotOptedIn, ", "))
+			}
+			if len(discovery.Filtered) > 0 {
+				pterm.Info.Printf("Skipping %d regions excluded by filters: %s\n",
+					len(discovery.Filtered), strings.Join(discovery.Filtered, ", "))
+			}
+			if len(discovery.Enabled) == 0 {
+				pterm.Error.Println("No enabled regions match the region filters")
+				os.Exit(1)
+			}
+
+			regionsToScan = discovery.Enabled
+			pterm.Info.Printf("Scanning %d enabled regions (this may take a while)...\n", len(regionsToScan))
+		} else if region == "" && len(opts.regions) > 0 {
+			regionsToScan = opts.regions
 		} else {
 			if region == "" {
 				result, _ := pterm.DefaultInteractiveTextInput.
@@ -62,15 +86,24 @@ var scanCmd = &cobra.Command{
 				}
 			}
 
-			if !isValidRegion(region) {
-				pterm.Warning.Printf("Region '%s' may not be valid. Valid regions: %s\n", 
-					region, strings.Join(validRegions[:5], ", ")+"...")
-				
+			discovery, err := discoverRegions(region, nil, nil)
+			warned := true
+			switch {
+			case err != nil:
+				pterm.Warning.Printf("Could not verify region '%s': %v\n", region, err)
+			case !contains(discovery.Enabled, region):
+				pterm.Warning.Printf("Region '%s' is not enabled for this account. Enabled regions: %s\n",
+					region, strings.Join(discovery.Enabled, ", "))
+			default:
+				warned = false
+			}
+
+			if warned {
 				confirm, _ := pterm.DefaultInteractiveConfirm.
 					WithDefaultText("Continue anyway?").
 					WithDefaultValue(false).
 					Show()
-				
+
 				if !confirm {
 					os.Exit(0)
 				}
@@ -79,33 +112,37 @@ var scanCmd = &cobra.Command{
 			regionsToScan = []string{region}
 		}
 
-		var allFindings []models.Finding
+		allFindings, scanErrs := scanRegions(regionsToScan, opts)
 
-		for _, reg := range regionsToScan {
-			findings := scanRegion(reg, deep, excludeIDs)
-			allFindings = append(allFindings, findings...)
+		if opts.s3 {
+			s3Findings, err := scanS3(regionsToScan[0])
+			if err != nil {
+				scanErrs = append(scanErrs, err)
+			}
+			allFindings = append(allFindings, s3Findings...)
+			sortFindings(allFindings)
 		}
 
 		filteredFindings := filterByRisk(allFindings, minRiskLevel)
 
-		if outputFile != "" {
-			err := writeOutput(filteredFindings, outputFormat, outputFile)
+		if opts.outputFile != "" {
+			err := writeOutput(filteredFindings, opts.outputFormat, opts.outputFile)
 			if err != nil {
 				pterm.Error.Printf("Failed to write output: %v\n", err)
 				os.Exit(1)
 			}
-			pterm.Success.Printf("Results written to %s\n", outputFile)
+			pterm.Success.Printf("Results written to %s\n", opts.outputFile)
 		}
 
 		pterm.Println()
 		pterm.DefaultSection.Println("Phase 3: Final Report")
 		
-		if outputFormat == "table" {
+		if opts.outputFormat == "table" {
 			ui.PrintFindings(filteredFindings)
-		} else if outputFormat == "json" {
+		} else if opts.outputFormat == "json" {
 			jsonData, _ := json.MarshalIndent(filteredFindings, "", "  ")
 			fmt.Println(string(jsonData))
-		} else if outputFormat == "csv" {
+		} else if opts.outputFormat == "csv" {
 			writeCSVToStdout(filteredFindings)
 		}
 
@@ -119,60 +156,166 @@ var scanCmd = &cobra.Command{
 				countByRisk(filteredFindings, models.RiskLow),
 			),
 		)
+
+		for _, err := range scanErrs {
+			pterm.Error.Println(err)
+		}
+
+		if err := notify(notifyOpts, filteredFindings, scanErrs); err != nil {
+			pterm.Error.Printf("Failed to send notification: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(scanErrs) > 0 {
+			pterm.Error.Printf("Scan incomplete: %d errors\n", len(scanErrs))
+			os.Exit(1)
+		}
 	},
 }
 
-func scanRegion(region string, deep bool, excludeIDs []string) []models.Finding {
+// scanOptions holds the effective scan settings after merging the config
+// file with command-line flags.
+type scanOptions struct {
+	region       string
+	regions      []string
+	allRegions   bool
+	deep         bool
+	outputFormat string
+	outputFile   string
+	minRisk      string
+	excludeIDs   []string
+	excludeTags  []scanner.TagFilter
+	s3           bool
+	ports        []scanner.AIPort
+	concurrency  int
+
+	includeRegions []string
+	excludeRegions []string
+
+	probe        bool
+	probeTimeout time.Duration
+
+	kubeconfig string
+}
+
+// resolveScanOptions reads the scan flags and fills in anything not set
+// explicitly on the command line from cfg. Explicit flags always win.
+func resolveScanOptions(cmd *cobra.Command, cfg *config.Config) scanOptions {
+	flags := cmd.Flags()
+
+	var opts scanOptions
+	opts.region, _ = flags.GetString("region")
+	opts.allRegions, _ = flags.GetBool("all-regions")
+	opts.deep, _ = flags.GetBool("deep")
+	opts.outputFormat, _ = flags.GetString("format")
+	opts.outputFile, _ = flags.GetString("output")
+	opts.minRisk, _ = flags.GetString("min-risk")
+	opts.excludeIDs, _ = flags.GetStringSlice("exclude-ids")
+	opts.s3, _ = flags.GetBool("s3")
+	opts.concurrency, _ = flags.GetInt("concurrency")
+	opts.includeRegions, _ = flags.GetStringSlice("include-regions")
+	opts.probe, _ = flags.GetBool("probe")
+	opts.probeTimeout, _ = flags.GetDuration("probe-timeout")
+	opts.excludeRegions, _ = flags.GetStringSlice("exclude-regions")
+	opts.kubeconfig, _ = flags.GetString("kubeconfig")
+	excludeTags, _ := flags.GetStringSlice("exclude-tags")
+
+	if !flags.Changed("region") && !flags.Changed("all-regions") {
+		opts.regions = cfg.Regions
+	}
+	if !flags.Changed("deep") && cfg.DeepScan {
+		opts.deep = true
+	}
+	if !flags.Changed("format") && cfg.OutputFormat != "" {
+		opts.outputFormat = cfg.OutputFormat
+	}
+	if !flags.Changed("min-risk") && cfg.MinRisk != "" {
+		opts.minRisk = cfg.MinRisk
+	}
+	if !flags.Changed("include-regions") && len(cfg.IncludeRegions) > 0 {
+		opts.includeRegions = cfg.IncludeRegions
+	}
+	if !flags.Changed("exclude-regions") && len(cfg.ExcludeRegions) > 0 {
+		opts.excludeRegions = cfg.ExcludeRegions
+	}
+	if !flags.Changed("probe") && cfg.Probe {
+		opts.probe = true
+	}
+	if !flags.Changed("kubeconfig") && cfg.Kubeconfig != "" {
+		opts.kubeconfig = cfg.Kubeconfig
+	}
+	if !flags.Changed("concurrency") && cfg.Concurrency > 0 {
+		opts.concurrency = cfg.Concurrency
+	}
+	if opts.concurrency < 1 {
+		opts.concurrency = 1
+	}
+	if !flags.Changed("exclude-ids") && len(cfg.Exclude.Instances) > 0 {
+		opts.excludeIDs = cfg.Exclude.Instances
+	}
+	if flags.Changed("exclude-tags") {
+		for _, t := range excludeTags {
+			opts.excludeTags = append(opts.excludeTags, scanner.ParseTagFilter(t))
+		}
+	} else {
+		opts.excludeTags = scanner.TagFiltersFromMap(cfg.Exclude.Tags)
+	}
+
+	var custom []scanner.AIPort
+	for _, p := range cfg.CustomPorts {
+		custom = append(custom, scanner.AIPort{
+			Port:        p.Port,
+			Name:        p.Name,
+			Risk:        models.RiskLevel(p.Risk),
+			Description: p.Description,
+		})
+	}
+	opts.ports = scanner.MergePorts(scanner.DefaultAIPorts, custom)
+
+	return opts
+}
+
+// scanS3 runs the bucket scan once for the whole account. S3 is global, so
+// region only selects the endpoint used to list buckets. Failures are
+// returned so they count towards the exit code like a failed region.
+func scanS3(region string) ([]models.Finding, error) {
 	pterm.Println()
-	pterm.DefaultSection.Printf("Phase 1: Initialization (%s)", region)
+	pterm.DefaultSection.Println("Phase 2b: S3 Analysis (global)")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
 	defer cancel()
 
-	spinner := ui.StartSpinner("Connecting to AWS Region: " + region)
+	spinner := ui.StartSpinner("Connecting to AWS S3...")
 	awsClient, err := aws.NewClient(ctx, region)
 	if err != nil {
 		spinner.Fail("Error initializing AWS client: " + err.Error())
-		pterm.Error.Printf("Skipping region %s\n", region)
-		return nil
+		return nil, fmt.Errorf("S3: %w", err)
 	}
-	spinner.Success("Connected to AWS (" + region + ")")
 
-	pterm.Println()
-	pterm.DefaultSection.Printf("Phase 2: Discovery & Analysis (%s)", region)
+	return runS3Scan(ctx, awsClient, spinner)
+}
 
-	spinner = ui.StartSpinner("Hunting for Shadow AI artifacts...")
-	scn := scanner.New(awsClient, deep)
-	findings, err := scn.Scan(ctx, spinner)
+func runS3Scan(ctx context.Context, awsClient *aws.Client, spinner *pterm.SpinnerPrinter) ([]models.Finding, error) {
+	scn := scanner.New(awsClient, false)
+	scn.S3 = true
+	findings, err := scn.ScanGlobal(ctx, spinner)
 	if err != nil {
-		spinner.Fail("Scan failed: " + err.Error())
-		return nil
+		spinner.Fail("S3 scan failed: " + err.Error())
+		return findings, fmt.Errorf("S3: %w", err)
 	}
-	spinner.Success("Scan Complete")
-
-	if len(excludeIDs) > 0 {
-		filtered := []models.Finding{}
-		for _, f := range findings {
-			excluded := false
-			for _, id := range excludeIDs {
-				if f.InstanceID == id {
-					excluded = true
-					break
-				}
-			}
-			if !excluded {
-				filtered = append(filtered, f)
-			}
-		}
-		findings = filtered
+
+	buckets := map[string]struct{}{}
+	for _, f := range findings {
+		buckets[f.InstanceID] = struct{}{}
 	}
+	spinner.Success(fmt.Sprintf("S3 Scan Complete (%d AI-related buckets)", len(buckets)))
 
-	return findings
+	return findings, nil
 }
 
-func isValidRegion(region string) bool {
-	for _, r := range validRegions {
-		if r == region {
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
 			return true
 		}
 	}
@@ -195,18 +338,11 @@ func parseRiskLevel(risk string) models.RiskLevel {
 }
 
 func filterByRisk(findings []models.Finding, minRisk models.RiskLevel) []models.Finding {
-	riskOrder := map[models.RiskLevel]int{
-		models.RiskCritical: 4,
-		models.RiskHigh:     3,
-		models.RiskMedium:   2,
-		models.RiskLow:      1,
-	}
-
-	minLevel := riskOrder[minRisk]
+	minLevel := minRisk.Rank()
 	filtered := []models.Finding{}
 
 	for _, f := range findings {
-		if riskOrder[f.Risk] >= minLevel {
+		if f.Risk.Rank() >= minLevel {
 			filtered = append(filtered, f)
 		}
 	}
@@ -294,7 +430,18 @@ func init() {
 	scanCmd.Flags().String("format", "table", "Output format: table, json, or csv")
 	scanCmd.Flags().StringP("output", "o", "", "Write results to file")
 	scanCmd.Flags().String("min-risk", "LOW", "Minimum risk level to show (LOW, MEDIUM, HIGH, CRITICAL)")
-	scanCmd.Flags().Bool("all-regions", false, "Scan all AWS regions")
+	scanCmd.Flags().Bool("all-regions", false, "Scan all regions enabled for the account")
+	scanCmd.Flags().StringSlice("include-regions", []string{}, "Only scan enabled regions matching these patterns (e.g. eu-*,us-*)")
+	scanCmd.Flags().StringSlice("exclude-regions", []string{}, "Skip enabled regions matching these patterns")
+	scanCmd.Flags().Int("concurrency", 4, "Number of regions to scan in parallel")
 	scanCmd.Flags().StringSlice("exclude-ids", []string{}, "Instance IDs to exclude from scan")
+	scanCmd.Flags().StringSlice("exclude-tags", []string{}, "Instance tags to exclude from scan (key or key=value, wildcards allowed)")
+	scanCmd.Flags().Bool("probe", false, "Send read-only HTTP requests to exposed ports to confirm what is listening")
+	scanCmd.Flags().Duration("probe-timeout", 3*time.Second, "Timeout for each probe request")
+	scanCmd.Flags().String("kubeconfig", "", "Kubeconfig whose contexts are used to check Deployments and Services in matching EKS clusters")
 	scanCmd.Flags().Bool("s3", false, "Scan S3 buckets for AI models")
+	scanCmd.Flags().String("notify", "", "Send results to a notification target: slack")
+	scanCmd.Flags().String("notify-min-risk", "HIGH", "Only notify when findings at or above this risk exist")
+	scanCmd.Flags().Bool("notify-clean", false, "Also notify when the scan finds nothing")
+	scanCmd.Flags().StringP("config", "c", "", "Path to config file (default: ./ghostweights.yaml, then $XDG_CONFIG_HOME/ghostweights/config.yaml)")
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws"
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/pterm/pterm"
+)
+
+func TestRunS3ScanReturnsListError(t *testing.T) {
+	denied := errors.New("AccessDenied")
+	c := &aws.Client{Region: "us-east-1", S3: &fake.S3{ListErr: denied}}
+	spinner, _ := pterm.DefaultSpinner.WithWriter(io.Discard).Start("S3")
+
+	_, err := runS3Scan(context.Background(), c, spinner)
+	if !errors.Is(err, denied) {
+		t.Fatalf("err = %v, want %v", err, denied)
+	}
+}
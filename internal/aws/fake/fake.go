@@ -0,0 +1,994 @@
+// Package fake provides in-memory implementations of the AWS client
+// interfaces in internal/aws so the scanner can be tested offline.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	client "github.com/K0NGR3SS/ghostweights/internal/aws"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	astypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	agenttypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+)
+
+var (
+	_ client.EC2API = (*EC2)(nil)
+	_ client.SSMAPI = (*SSM)(nil)
+	_ client.S3API  = (*S3)(nil)
+	_ client.ELBAPI = (*ELB)(nil)
+
+	_ client.SageMakerAPI    = (*SageMaker)(nil)
+	_ client.BedrockAPI      = (*Bedrock)(nil)
+	_ client.BedrockAgentAPI = (*BedrockAgent)(nil)
+	_ client.LambdaAPI       = (*Lambda)(nil)
+	_ client.ECRAPI          = (*ECR)(nil)
+	_ client.ECSAPI          = (*ECS)(nil)
+	_ client.EKSAPI          = (*EKS)(nil)
+	_ client.AutoScalingAPI  = (*AutoScaling)(nil)
+)
+
+// EC2 serves a fixed set of instances and security groups.
+type EC2 struct {
+	Instances      []ec2types.Instance
+	Images         []ec2types.Image
+	SecurityGroups []ec2types.SecurityGroup
+	Regions        []ec2types.Region
+	Subnets        []ec2types.Subnet
+	RouteTables    []ec2types.RouteTable
+	NetworkAcls    []ec2types.NetworkAcl
+	PrefixLists    map[string][]string
+
+	// UserData maps instance IDs to base64-encoded user data, as the API
+	// returns it.
+	UserData map[string]string
+
+	LaunchTemplates        []ec2types.LaunchTemplate
+	LaunchTemplateVersions []ec2types.LaunchTemplateVersion
+	SpotInstanceRequests   []ec2types.SpotInstanceRequest
+	SpotFleetRequests      []ec2types.SpotFleetRequestConfig
+
+	mu    sync.Mutex
+	Calls map[string]int
+}
+
+func (f *EC2) record(op string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Calls == nil {
+		f.Calls = map[string]int{}
+	}
+	f.Calls[op]++
+}
+
+// DescribeInstances returns every instance in a single reservation. Only
+// InstanceIds and the instance-state-name filter are honoured.
+func (f *EC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.record("DescribeInstances")
+
+	var states []string
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "instance-state-name" {
+			states = filter.Values
+		}
+	}
+
+	var instances []ec2types.Instance
+	for _, i := range f.Instances {
+		if len(states) > 0 && !containsState(states, i.State) {
+			continue
+		}
+		if len(params.InstanceIds) > 0 && !slices.Contains(params.InstanceIds, aws.ToString(i.InstanceId)) {
+			continue
+		}
+		instances = append(instances, i)
+	}
+
+	return &ec2.DescribeInstancesOutput{
+		Reservations: []ec2types.Reservation{{Instances: instances}},
+	}, nil
+}
+
+// DescribeImages returns the images matching ImageIds and the image-id
+// filter, or all of them without either. Unknown IDs are left out rather
+// than rejected.
+func (f *EC2) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	f.record("DescribeImages")
+
+	ids := params.ImageIds
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "image-id" {
+			ids = append(ids, filter.Values...)
+		}
+	}
+
+	var images []ec2types.Image
+	for _, img := range f.Images {
+		if len(ids) > 0 && !slices.Contains(ids, aws.ToString(img.ImageId)) {
+			continue
+		}
+		images = append(images, img)
+	}
+	return &ec2.DescribeImagesOutput{Images: images}, nil
+}
+
+// DescribeInstanceAttribute serves the userData attribute from UserData.
+// Other attributes are rejected.
+func (f *EC2) DescribeInstanceAttribute(ctx context.Context, params *ec2.DescribeInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error) {
+	f.record("DescribeInstanceAttribute")
+
+	if params.Attribute != ec2types.InstanceAttributeNameUserData {
+		return nil, &smithy.GenericAPIError{Code: "InvalidParameterValue", Message: "unsupported attribute"}
+	}
+	out := &ec2.DescribeInstanceAttributeOutput{InstanceId: params.InstanceId, UserData: &ec2types.AttributeValue{}}
+	if data, ok := f.UserData[aws.ToString(params.InstanceId)]; ok {
+		out.UserData.Value = aws.String(data)
+	}
+	return out, nil
+}
+
+func containsState(states []string, state *ec2types.InstanceState) bool {
+	name := string(ec2types.InstanceStateNameRunning)
+	if state != nil {
+		name = string(state.Name)
+	}
+	for _, s := range states {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeSecurityGroups returns the groups matching GroupIds, or all of
+// them when no IDs are given.
+func (f *EC2) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	f.record("DescribeSecurityGroups")
+
+	if len(params.GroupIds) == 0 {
+		return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: f.SecurityGroups}, nil
+	}
+
+	var groups []ec2types.SecurityGroup
+	for _, id := range params.GroupIds {
+		for _, sg := range f.SecurityGroups {
+			if aws.ToString(sg.GroupId) == id {
+				groups = append(groups, sg)
+			}
+		}
+	}
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: groups}, nil
+}
+
+// DescribeSubnets returns every subnet; filters are ignored.
+func (f *EC2) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	f.record("DescribeSubnets")
+	return &ec2.DescribeSubnetsOutput{Subnets: f.Subnets}, nil
+}
+
+// DescribeRouteTables returns every route table; filters are ignored.
+func (f *EC2) DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	f.record("DescribeRouteTables")
+	return &ec2.DescribeRouteTablesOutput{RouteTables: f.RouteTables}, nil
+}
+
+// DescribeNetworkAcls returns every network ACL; filters are ignored.
+func (f *EC2) DescribeNetworkAcls(ctx context.Context, params *ec2.DescribeNetworkAclsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkAclsOutput, error) {
+	f.record("DescribeNetworkAcls")
+	return &ec2.DescribeNetworkAclsOutput{NetworkAcls: f.NetworkAcls}, nil
+}
+
+// GetManagedPrefixListEntries returns the CIDRs in PrefixLists, or an
+// error for unknown lists.
+func (f *EC2) GetManagedPrefixListEntries(ctx context.Context, params *ec2.GetManagedPrefixListEntriesInput, optFns ...func(*ec2.Options)) (*ec2.GetManagedPrefixListEntriesOutput, error) {
+	f.record("GetManagedPrefixListEntries")
+
+	cidrs, ok := f.PrefixLists[aws.ToString(params.PrefixListId)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "InvalidPrefixListID.NotFound", Message: "prefix list not found"}
+	}
+	out := &ec2.GetManagedPrefixListEntriesOutput{}
+	for _, c := range cidrs {
+		out.Entries = append(out.Entries, ec2types.PrefixListEntry{Cidr: aws.String(c)})
+	}
+	return out, nil
+}
+
+// DescribeRegions returns Regions. Without AllRegions, regions that are not
+// opted in are left out, as in the real API.
+func (f *EC2) DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	f.record("DescribeRegions")
+
+	var regions []ec2types.Region
+	for _, r := range f.Regions {
+		if !aws.ToBool(params.AllRegions) && aws.ToString(r.OptInStatus) == "not-opted-in" {
+			continue
+		}
+		regions = append(regions, r)
+	}
+	return &ec2.DescribeRegionsOutput{Regions: regions}, nil
+}
+
+// DescribeLaunchTemplates returns every launch template; filters are
+// ignored.
+func (f *EC2) DescribeLaunchTemplates(ctx context.Context, params *ec2.DescribeLaunchTemplatesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	f.record("DescribeLaunchTemplates")
+	return &ec2.DescribeLaunchTemplatesOutput{LaunchTemplates: f.LaunchTemplates}, nil
+}
+
+// DescribeLaunchTemplateVersions returns every version of the template
+// named by LaunchTemplateId or LaunchTemplateName. Versions is ignored.
+func (f *EC2) DescribeLaunchTemplateVersions(ctx context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	f.record("DescribeLaunchTemplateVersions")
+
+	var versions []ec2types.LaunchTemplateVersion
+	for _, v := range f.LaunchTemplateVersions {
+		if params.LaunchTemplateId != nil && aws.ToString(v.LaunchTemplateId) != aws.ToString(params.LaunchTemplateId) ||
+			params.LaunchTemplateName != nil && aws.ToString(v.LaunchTemplateName) != aws.ToString(params.LaunchTemplateName) {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return &ec2.DescribeLaunchTemplateVersionsOutput{LaunchTemplateVersions: versions}, nil
+}
+
+// DescribeSpotInstanceRequests returns the requests whose state matches
+// the state filter, or all of them without one.
+func (f *EC2) DescribeSpotInstanceRequests(ctx context.Context, params *ec2.DescribeSpotInstanceRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotInstanceRequestsOutput, error) {
+	f.record("DescribeSpotInstanceRequests")
+
+	var states []string
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "state" {
+			states = filter.Values
+		}
+	}
+
+	var requests []ec2types.SpotInstanceRequest
+	for _, r := range f.SpotInstanceRequests {
+		if len(states) > 0 && !slices.Contains(states, string(r.State)) {
+			continue
+		}
+		requests = append(requests, r)
+	}
+	return &ec2.DescribeSpotInstanceRequestsOutput{SpotInstanceRequests: requests}, nil
+}
+
+// DescribeSpotFleetRequests returns every Spot Fleet request, whatever its
+// state, as the real API does.
+func (f *EC2) DescribeSpotFleetRequests(ctx context.Context, params *ec2.DescribeSpotFleetRequestsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotFleetRequestsOutput, error) {
+	f.record("DescribeSpotFleetRequests")
+	return &ec2.DescribeSpotFleetRequestsOutput{SpotFleetRequestConfigs: f.SpotFleetRequests}, nil
+}
+
+// SSM returns canned RunShellScript output per instance. Instances without
+// an entry in Outputs report a Failed invocation.
+type SSM struct {
+	Outputs  map[string]string
+	Statuses map[string]ssmtypes.CommandInvocationStatus
+	SendErr  error
+
+	// PingStatus lists the instances known to SSM. When nil, every instance
+	// in Outputs or Statuses is reported Online.
+	PingStatus map[string]ssmtypes.PingStatus
+	// Throttle makes the first N GetCommandInvocation calls fail with a
+	// throttling error.
+	Throttle int
+
+	mu    sync.Mutex
+	Sent  []*ssm.SendCommandInput
+	Polls int
+}
+
+// maxSendTargets mirrors the SendCommand limit on InstanceIds.
+const maxSendTargets = 50
+
+func (f *SSM) SendCommand(ctx context.Context, params *ssm.SendCommandInput, optFns ...func(*ssm.Options)) (*ssm.SendCommandOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.SendErr != nil {
+		return nil, f.SendErr
+	}
+	if len(params.InstanceIds) > maxSendTargets {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "too many instance IDs"}
+	}
+	f.Sent = append(f.Sent, params)
+
+	return &ssm.SendCommandOutput{
+		Command: &ssmtypes.Command{CommandId: aws.String(fmt.Sprintf("cmd-%d", len(f.Sent)))},
+	}, nil
+}
+
+func (f *SSM) GetCommandInvocation(ctx context.Context, params *ssm.GetCommandInvocationInput, optFns ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error) {
+	f.mu.Lock()
+	f.Polls++
+	throttled := f.Polls <= f.Throttle
+	f.mu.Unlock()
+	if throttled {
+		return nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	}
+
+	instanceID := aws.ToString(params.InstanceId)
+
+	status, ok := f.Statuses[instanceID]
+	if !ok {
+		status = ssmtypes.CommandInvocationStatusFailed
+		if _, hasOutput := f.Outputs[instanceID]; hasOutput {
+			status = ssmtypes.CommandInvocationStatusSuccess
+		}
+	}
+
+	return &ssm.GetCommandInvocationOutput{
+		CommandId:             params.CommandId,
+		InstanceId:            params.InstanceId,
+		Status:                status,
+		StandardOutputContent: aws.String(f.Outputs[instanceID]),
+	}, nil
+}
+
+func (f *SSM) DescribeInstanceInformation(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+	status := f.PingStatus
+	if status == nil {
+		status = map[string]ssmtypes.PingStatus{}
+		for id := range f.Outputs {
+			status[id] = ssmtypes.PingStatusOnline
+		}
+		for id := range f.Statuses {
+			status[id] = ssmtypes.PingStatusOnline
+		}
+	}
+
+	var want []string
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Key) == "PingStatus" {
+			want = filter.Values
+		}
+	}
+
+	var list []ssmtypes.InstanceInformation
+	for id, ping := range status {
+		if len(want) > 0 && !slices.Contains(want, string(ping)) {
+			continue
+		}
+		list = append(list, ssmtypes.InstanceInformation{InstanceId: aws.String(id), PingStatus: ping})
+	}
+	return &ssm.DescribeInstanceInformationOutput{InstanceInformationList: list}, nil
+}
+
+// Bucket is the state of a single fake S3 bucket.
+type Bucket struct {
+	Region    string
+	Grants    []s3types.Grant
+	Policy    string
+	Encrypted bool
+	Objects   []s3types.Object
+}
+
+// S3 serves buckets from memory. Per-call region options are ignored.
+type S3 struct {
+	Buckets map[string]*Bucket
+
+	// ListErr, when set, is returned by ListBuckets.
+	ListErr error
+}
+
+var errNoSuchBucket = errors.New("NoSuchBucket")
+
+func (f *S3) bucket(name *string) (*Bucket, error) {
+	b, ok := f.Buckets[aws.ToString(name)]
+	if !ok {
+		return nil, errNoSuchBucket
+	}
+	return b, nil
+}
+
+func (f *S3) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	if f.ListErr != nil {
+		return nil, f.ListErr
+	}
+	var buckets []s3types.Bucket
+	for name := range f.Buckets {
+		buckets = append(buckets, s3types.Bucket{Name: aws.String(name)})
+	}
+	return &s3.ListBucketsOutput{Buckets: buckets}, nil
+}
+
+func (f *S3) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	b, err := f.bucket(params.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	region := b.Region
+	if region == "us-east-1" {
+		region = ""
+	}
+	return &s3.GetBucketLocationOutput{LocationConstraint: s3types.BucketLocationConstraint(region)}, nil
+}
+
+func (f *S3) GetBucketAcl(ctx context.Context, params *s3.GetBucketAclInput, optFns ...func(*s3.Options)) (*s3.GetBucketAclOutput, error) {
+	b, err := f.bucket(params.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.GetBucketAclOutput{Grants: b.Grants}, nil
+}
+
+func (f *S3) GetBucketPolicy(ctx context.Context, params *s3.GetBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+	b, err := f.bucket(params.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if b.Policy == "" {
+		return nil, errors.New("NoSuchBucketPolicy")
+	}
+	return &s3.GetBucketPolicyOutput{Policy: aws.String(b.Policy)}, nil
+}
+
+func (f *S3) GetBucketEncryption(ctx context.Context, params *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+	b, err := f.bucket(params.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !b.Encrypted {
+		return nil, errors.New("ServerSideEncryptionConfigurationNotFoundError")
+	}
+	return &s3.GetBucketEncryptionOutput{
+		ServerSideEncryptionConfiguration: &s3types.ServerSideEncryptionConfiguration{
+			Rules: []s3types.ServerSideEncryptionRule{{
+				ApplyServerSideEncryptionByDefault: &s3types.ServerSideEncryptionByDefault{
+					SSEAlgorithm: s3types.ServerSideEncryptionAes256,
+				},
+			}},
+		},
+	}, nil
+}
+
+func (f *S3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	b, err := f.bucket(params.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.ListObjectsV2Output{Contents: b.Objects}, nil
+}
+
+// LoadBalancer is a fake ELBv2 load balancer with its listeners, listener
+// rules and target groups. ARNs must be set by the test; Rules is keyed by
+// listener ARN.
+type LoadBalancer struct {
+	LB           elbv2types.LoadBalancer
+	Listeners    []elbv2types.Listener
+	Rules        map[string][]elbv2types.Rule
+	TargetGroups []TargetGroup
+}
+
+// TargetGroup is a target group and its registered targets.
+type TargetGroup struct {
+	Group   elbv2types.TargetGroup
+	Targets []elbv2types.TargetHealthDescription
+}
+
+// ELB serves load balancers from memory.
+type ELB struct {
+	LoadBalancers []LoadBalancer
+}
+
+func (f *ELB) DescribeLoadBalancers(ctx context.Context, params *elbv2.DescribeLoadBalancersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeLoadBalancersOutput, error) {
+	out := &elbv2.DescribeLoadBalancersOutput{}
+	for _, lb := range f.LoadBalancers {
+		out.LoadBalancers = append(out.LoadBalancers, lb.LB)
+	}
+	return out, nil
+}
+
+func (f *ELB) DescribeListeners(ctx context.Context, params *elbv2.DescribeListenersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeListenersOutput, error) {
+	for _, lb := range f.LoadBalancers {
+		if aws.ToString(lb.LB.LoadBalancerArn) == aws.ToString(params.LoadBalancerArn) {
+			return &elbv2.DescribeListenersOutput{Listeners: lb.Listeners}, nil
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "LoadBalancerNotFound", Message: "load balancer not found"}
+}
+
+func (f *ELB) DescribeRules(ctx context.Context, params *elbv2.DescribeRulesInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeRulesOutput, error) {
+	for _, lb := range f.LoadBalancers {
+		for _, l := range lb.Listeners {
+			if aws.ToString(l.ListenerArn) == aws.ToString(params.ListenerArn) {
+				return &elbv2.DescribeRulesOutput{Rules: lb.Rules[*l.ListenerArn]}, nil
+			}
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "ListenerNotFound", Message: "listener not found"}
+}
+
+// DescribeTargetGroups honours only the LoadBalancerArn filter.
+func (f *ELB) DescribeTargetGroups(ctx context.Context, params *elbv2.DescribeTargetGroupsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetGroupsOutput, error) {
+	out := &elbv2.DescribeTargetGroupsOutput{}
+	for _, lb := range f.LoadBalancers {
+		if params.LoadBalancerArn != nil && aws.ToString(lb.LB.LoadBalancerArn) != *params.LoadBalancerArn {
+			continue
+		}
+		for _, tg := range lb.TargetGroups {
+			out.TargetGroups = append(out.TargetGroups, tg.Group)
+		}
+	}
+	return out, nil
+}
+
+func (f *ELB) DescribeTargetHealth(ctx context.Context, params *elbv2.DescribeTargetHealthInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetHealthOutput, error) {
+	for _, lb := range f.LoadBalancers {
+		for _, tg := range lb.TargetGroups {
+			if aws.ToString(tg.Group.TargetGroupArn) == aws.ToString(params.TargetGroupArn) {
+				return &elbv2.DescribeTargetHealthOutput{TargetHealthDescriptions: tg.Targets}, nil
+			}
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "TargetGroupNotFound", Message: "target group not found"}
+}
+
+// SageMaker serves resources from Describe outputs keyed by name (domain ID
+// for Domains). List calls return every resource in name order.
+type SageMaker struct {
+	Notebooks       map[string]*sagemaker.DescribeNotebookInstanceOutput
+	Endpoints       map[string]*sagemaker.DescribeEndpointOutput
+	EndpointConfigs map[string]*sagemaker.DescribeEndpointConfigOutput
+	Domains         map[string]*sagemaker.DescribeDomainOutput
+	TrainingJobs    map[string]*sagemaker.DescribeTrainingJobOutput
+	Models          map[string]*sagemaker.DescribeModelOutput
+}
+
+func lookup[T any](m map[string]*T, name *string, kind string) (*T, error) {
+	v, ok := m[aws.ToString(name)]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("%s %s not found", kind, aws.ToString(name))}
+	}
+	return v, nil
+}
+
+func (f *SageMaker) ListNotebookInstances(ctx context.Context, params *sagemaker.ListNotebookInstancesInput, optFns ...func(*sagemaker.Options)) (*sagemaker.ListNotebookInstancesOutput, error) {
+	out := &sagemaker.ListNotebookInstancesOutput{}
+	for _, name := range slices.Sorted(maps.Keys(f.Notebooks)) {
+		out.NotebookInstances = append(out.NotebookInstances, smtypes.NotebookInstanceSummary{NotebookInstanceName: aws.String(name)})
+	}
+	return out, nil
+}
+
+func (f *SageMaker) DescribeNotebookInstance(ctx context.Context, params *sagemaker.DescribeNotebookInstanceInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeNotebookInstanceOutput, error) {
+	return lookup(f.Notebooks, params.NotebookInstanceName, "notebook instance")
+}
+
+func (f *SageMaker) ListEndpoints(ctx context.Context, params *sagemaker.ListEndpointsInput, optFns ...func(*sagemaker.Options)) (*sagemaker.ListEndpointsOutput, error) {
+	out := &sagemaker.ListEndpointsOutput{}
+	for _, name := range slices.Sorted(maps.Keys(f.Endpoints)) {
+		out.Endpoints = append(out.Endpoints, smtypes.EndpointSummary{EndpointName: aws.String(name)})
+	}
+	return out, nil
+}
+
+func (f *SageMaker) DescribeEndpoint(ctx context.Context, params *sagemaker.DescribeEndpointInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeEndpointOutput, error) {
+	return lookup(f.Endpoints, params.EndpointName, "endpoint")
+}
+
+func (f *SageMaker) DescribeEndpointConfig(ctx context.Context, params *sagemaker.DescribeEndpointConfigInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeEndpointConfigOutput, error) {
+	return lookup(f.EndpointConfigs, params.EndpointConfigName, "endpoint config")
+}
+
+func (f *SageMaker) ListDomains(ctx context.Context, params *sagemaker.ListDomainsInput, optFns ...func(*sagemaker.Options)) (*sagemaker.ListDomainsOutput, error) {
+	out := &sagemaker.ListDomainsOutput{}
+	for _, id := range slices.Sorted(maps.Keys(f.Domains)) {
+		out.Domains = append(out.Domains, smtypes.DomainDetails{DomainId: aws.String(id), DomainName: f.Domains[id].DomainName})
+	}
+	return out, nil
+}
+
+func (f *SageMaker) DescribeDomain(ctx context.Context, params *sagemaker.DescribeDomainInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeDomainOutput, error) {
+	return lookup(f.Domains, params.DomainId, "domain")
+}
+
+// ListTrainingJobs honours CreationTimeAfter.
+func (f *SageMaker) ListTrainingJobs(ctx context.Context, params *sagemaker.ListTrainingJobsInput, optFns ...func(*sagemaker.Options)) (*sagemaker.ListTrainingJobsOutput, error) {
+	out := &sagemaker.ListTrainingJobsOutput{}
+	for _, name := range slices.Sorted(maps.Keys(f.TrainingJobs)) {
+		job := f.TrainingJobs[name]
+		if params.CreationTimeAfter != nil && job.CreationTime != nil && job.CreationTime.Before(*params.CreationTimeAfter) {
+			continue
+		}
+		out.TrainingJobSummaries = append(out.TrainingJobSummaries, smtypes.TrainingJobSummary{TrainingJobName: aws.String(name)})
+	}
+	return out, nil
+}
+
+func (f *SageMaker) DescribeTrainingJob(ctx context.Context, params *sagemaker.DescribeTrainingJobInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeTrainingJobOutput, error) {
+	return lookup(f.TrainingJobs, params.TrainingJobName, "training job")
+}
+
+func (f *SageMaker) ListModels(ctx context.Context, params *sagemaker.ListModelsInput, optFns ...func(*sagemaker.Options)) (*sagemaker.ListModelsOutput, error) {
+	out := &sagemaker.ListModelsOutput{}
+	for _, name := range slices.Sorted(maps.Keys(f.Models)) {
+		out.Models = append(out.Models, smtypes.ModelSummary{ModelName: aws.String(name)})
+	}
+	return out, nil
+}
+
+func (f *SageMaker) DescribeModel(ctx context.Context, params *sagemaker.DescribeModelInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeModelOutput, error) {
+	return lookup(f.Models, params.ModelName, "model")
+}
+
+// Bedrock serves fixed model, throughput and guardrail lists. Availability
+// is keyed by model ID; models without an entry are not authorized. A nil
+// Logging means invocation logging is off.
+type Bedrock struct {
+	FoundationModels []bedrocktypes.FoundationModelSummary
+	Availability     map[string]*bedrock.GetFoundationModelAvailabilityOutput
+	CustomModels     []bedrocktypes.CustomModelSummary
+	Provisioned      []bedrocktypes.ProvisionedModelSummary
+	Guardrails       []bedrocktypes.GuardrailSummary
+	Logging          *bedrocktypes.LoggingConfig
+}
+
+func (f *Bedrock) ListFoundationModels(ctx context.Context, params *bedrock.ListFoundationModelsInput, optFns ...func(*bedrock.Options)) (*bedrock.ListFoundationModelsOutput, error) {
+	return &bedrock.ListFoundationModelsOutput{ModelSummaries: f.FoundationModels}, nil
+}
+
+func (f *Bedrock) GetFoundationModelAvailability(ctx context.Context, params *bedrock.GetFoundationModelAvailabilityInput, optFns ...func(*bedrock.Options)) (*bedrock.GetFoundationModelAvailabilityOutput, error) {
+	if out, ok := f.Availability[aws.ToString(params.ModelId)]; ok {
+		return out, nil
+	}
+	return &bedrock.GetFoundationModelAvailabilityOutput{
+		ModelId:                 params.ModelId,
+		AgreementAvailability:   &bedrocktypes.AgreementAvailability{Status: bedrocktypes.AgreementStatusNotAvailable},
+		AuthorizationStatus:     bedrocktypes.AuthorizationStatusNotAuthorized,
+		EntitlementAvailability: bedrocktypes.EntitlementAvailabilityNotAvailable,
+	}, nil
+}
+
+func (f *Bedrock) ListCustomModels(ctx context.Context, params *bedrock.ListCustomModelsInput, optFns ...func(*bedrock.Options)) (*bedrock.ListCustomModelsOutput, error) {
+	return &bedrock.ListCustomModelsOutput{ModelSummaries: f.CustomModels}, nil
+}
+
+func (f *Bedrock) ListProvisionedModelThroughputs(ctx context.Context, params *bedrock.ListProvisionedModelThroughputsInput, optFns ...func(*bedrock.Options)) (*bedrock.ListProvisionedModelThroughputsOutput, error) {
+	return &bedrock.ListProvisionedModelThroughputsOutput{ProvisionedModelSummaries: f.Provisioned}, nil
+}
+
+func (f *Bedrock) ListGuardrails(ctx context.Context, params *bedrock.ListGuardrailsInput, optFns ...func(*bedrock.Options)) (*bedrock.ListGuardrailsOutput, error) {
+	return &bedrock.ListGuardrailsOutput{Guardrails: f.Guardrails}, nil
+}
+
+func (f *Bedrock) GetModelInvocationLoggingConfiguration(ctx context.Context, params *bedrock.GetModelInvocationLoggingConfigurationInput, optFns ...func(*bedrock.Options)) (*bedrock.GetModelInvocationLoggingConfigurationOutput, error) {
+	return &bedrock.GetModelInvocationLoggingConfigurationOutput{LoggingConfig: f.Logging}, nil
+}
+
+// BedrockAgent serves fixed agent and knowledge base lists. DataSources is
+// keyed by knowledge base ID.
+type BedrockAgent struct {
+	Agents         []agenttypes.AgentSummary
+	KnowledgeBases []agenttypes.KnowledgeBaseSummary
+	DataSources    map[string][]agenttypes.DataSource
+}
+
+func (f *BedrockAgent) ListAgents(ctx context.Context, params *bedrockagent.ListAgentsInput, optFns ...func(*bedrockagent.Options)) (*bedrockagent.ListAgentsOutput, error) {
+	return &bedrockagent.ListAgentsOutput{AgentSummaries: f.Agents}, nil
+}
+
+func (f *BedrockAgent) ListKnowledgeBases(ctx context.Context, params *bedrockagent.ListKnowledgeBasesInput, optFns ...func(*bedrockagent.Options)) (*bedrockagent.ListKnowledgeBasesOutput, error) {
+	return &bedrockagent.ListKnowledgeBasesOutput{KnowledgeBaseSummaries: f.KnowledgeBases}, nil
+}
+
+func (f *BedrockAgent) ListDataSources(ctx context.Context, params *bedrockagent.ListDataSourcesInput, optFns ...func(*bedrockagent.Options)) (*bedrockagent.ListDataSourcesOutput, error) {
+	out := &bedrockagent.ListDataSourcesOutput{}
+	for _, ds := range f.DataSources[aws.ToString(params.KnowledgeBaseId)] {
+		out.DataSourceSummaries = append(out.DataSourceSummaries, agenttypes.DataSourceSummary{
+			DataSourceId:    ds.DataSourceId,
+			KnowledgeBaseId: params.KnowledgeBaseId,
+			Name:            ds.Name,
+		})
+	}
+	return out, nil
+}
+
+func (f *BedrockAgent) GetDataSource(ctx context.Context, params *bedrockagent.GetDataSourceInput, optFns ...func(*bedrockagent.Options)) (*bedrockagent.GetDataSourceOutput, error) {
+	for _, ds := range f.DataSources[aws.ToString(params.KnowledgeBaseId)] {
+		if aws.ToString(ds.DataSourceId) == aws.ToString(params.DataSourceId) {
+			return &bedrockagent.GetDataSourceOutput{DataSource: &ds}, nil
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "data source not found"}
+}
+
+// Function is a Lambda function with its code location and URL configs.
+type Function struct {
+	Config lambdatypes.FunctionConfiguration
+	Code   *lambdatypes.FunctionCodeLocation
+	URLs   []lambdatypes.FunctionUrlConfig
+}
+
+// Lambda serves a fixed set of functions.
+type Lambda struct {
+	Functions []Function
+}
+
+func (f *Lambda) function(name *string) (*Function, error) {
+	for i := range f.Functions {
+		if aws.ToString(f.Functions[i].Config.FunctionName) == aws.ToString(name) {
+			return &f.Functions[i], nil
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "function not found"}
+}
+
+func (f *Lambda) ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	out := &lambda.ListFunctionsOutput{}
+	for _, fn := range f.Functions {
+		out.Functions = append(out.Functions, fn.Config)
+	}
+	return out, nil
+}
+
+func (f *Lambda) GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	fn, err := f.function(params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+	cfg := fn.Config
+	return &lambda.GetFunctionOutput{Configuration: &cfg, Code: fn.Code}, nil
+}
+
+func (f *Lambda) ListFunctionUrlConfigs(ctx context.Context, params *lambda.ListFunctionUrlConfigsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionUrlConfigsOutput, error) {
+	fn, err := f.function(params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+	return &lambda.ListFunctionUrlConfigsOutput{FunctionUrlConfigs: fn.URLs}, nil
+}
+
+// ECR serves repositories with optional policies, and image details and
+// manifests keyed by "repository@digest". GetDownloadUrlForLayer returns
+// BlobURL/digest, so tests can serve config blobs from an httptest server.
+type ECR struct {
+	Repositories []ecrtypes.Repository
+	Policies     map[string]string
+	Images       map[string]ecrtypes.ImageDetail
+	Manifests    map[string]string
+	BlobURL      string
+}
+
+func (f *ECR) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	return &ecr.DescribeRepositoriesOutput{Repositories: f.Repositories}, nil
+}
+
+func (f *ECR) GetRepositoryPolicy(ctx context.Context, params *ecr.GetRepositoryPolicyInput, optFns ...func(*ecr.Options)) (*ecr.GetRepositoryPolicyOutput, error) {
+	policy, ok := f.Policies[aws.ToString(params.RepositoryName)]
+	if !ok {
+		return nil, &ecrtypes.RepositoryPolicyNotFoundException{Message: aws.String("repository policy does not exist")}
+	}
+	return &ecr.GetRepositoryPolicyOutput{RepositoryName: params.RepositoryName, PolicyText: aws.String(policy)}, nil
+}
+
+// DescribeImages returns the requested images, or every image in the
+// repository when no IDs are given.
+func (f *ECR) DescribeImages(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+	repo := aws.ToString(params.RepositoryName)
+	out := &ecr.DescribeImagesOutput{}
+	if len(params.ImageIds) == 0 {
+		for _, key := range slices.Sorted(maps.Keys(f.Images)) {
+			name, digest, _ := strings.Cut(key, "@")
+			if name != repo {
+				continue
+			}
+			img := f.Images[key]
+			img.RepositoryName = aws.String(name)
+			img.ImageDigest = aws.String(digest)
+			out.ImageDetails = append(out.ImageDetails, img)
+		}
+		return out, nil
+	}
+	for _, id := range params.ImageIds {
+		img, ok := f.Images[repo+"@"+aws.ToString(id.ImageDigest)]
+		if !ok {
+			return nil, &smithy.GenericAPIError{Code: "ImageNotFoundException", Message: "image not found"}
+		}
+		out.ImageDetails = append(out.ImageDetails, img)
+	}
+	return out, nil
+}
+
+func (f *ECR) BatchGetImage(ctx context.Context, params *ecr.BatchGetImageInput, optFns ...func(*ecr.Options)) (*ecr.BatchGetImageOutput, error) {
+	out := &ecr.BatchGetImageOutput{}
+	for _, id := range params.ImageIds {
+		manifest, ok := f.Manifests[aws.ToString(params.RepositoryName)+"@"+aws.ToString(id.ImageDigest)]
+		if !ok {
+			out.Failures = append(out.Failures, ecrtypes.ImageFailure{ImageId: &id, FailureCode: ecrtypes.ImageFailureCodeImageNotFound})
+			continue
+		}
+		out.Images = append(out.Images, ecrtypes.Image{
+			RepositoryName: params.RepositoryName,
+			ImageId:        &id,
+			ImageManifest:  aws.String(manifest),
+		})
+	}
+	return out, nil
+}
+
+func (f *ECR) GetDownloadUrlForLayer(ctx context.Context, params *ecr.GetDownloadUrlForLayerInput, optFns ...func(*ecr.Options)) (*ecr.GetDownloadUrlForLayerOutput, error) {
+	if f.BlobURL == "" {
+		return nil, &ecrtypes.LayersNotFoundException{Message: aws.String("layer not found")}
+	}
+	return &ecr.GetDownloadUrlForLayerOutput{
+		DownloadUrl: aws.String(f.BlobURL + "/" + aws.ToString(params.LayerDigest)),
+		LayerDigest: params.LayerDigest,
+	}, nil
+}
+
+// ECS serves services grouped by cluster name and task definitions keyed by
+// "family:revision". DescribeTaskDefinition also accepts a bare family or an
+// ARN ending in family:revision; a bare family resolves to its highest
+// revision.
+type ECS struct {
+	Services        map[string][]ecstypes.Service
+	TaskDefinitions map[string]ecstypes.TaskDefinition
+}
+
+func (f *ECS) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	return &ecs.ListClustersOutput{ClusterArns: slices.Sorted(maps.Keys(f.Services))}, nil
+}
+
+func (f *ECS) ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	out := &ecs.ListServicesOutput{}
+	for _, svc := range f.Services[aws.ToString(params.Cluster)] {
+		out.ServiceArns = append(out.ServiceArns, aws.ToString(svc.ServiceName))
+	}
+	return out, nil
+}
+
+func (f *ECS) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	out := &ecs.DescribeServicesOutput{}
+	for _, svc := range f.Services[aws.ToString(params.Cluster)] {
+		if slices.Contains(params.Services, aws.ToString(svc.ServiceName)) {
+			out.Services = append(out.Services, svc)
+		}
+	}
+	return out, nil
+}
+
+func (f *ECS) ListTaskDefinitionFamilies(ctx context.Context, params *ecs.ListTaskDefinitionFamiliesInput, optFns ...func(*ecs.Options)) (*ecs.ListTaskDefinitionFamiliesOutput, error) {
+	families := map[string]bool{}
+	for _, td := range f.TaskDefinitions {
+		families[aws.ToString(td.Family)] = true
+	}
+	return &ecs.ListTaskDefinitionFamiliesOutput{Families: slices.Sorted(maps.Keys(families))}, nil
+}
+
+func (f *ECS) DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	ref := aws.ToString(params.TaskDefinition)
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	if td, ok := f.TaskDefinitions[ref]; ok {
+		return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: &td}, nil
+	}
+
+	var latest *ecstypes.TaskDefinition
+	for _, td := range f.TaskDefinitions {
+		if aws.ToString(td.Family) == ref && (latest == nil || td.Revision > latest.Revision) {
+			latest = &td
+		}
+	}
+	if latest == nil {
+		return nil, &smithy.GenericAPIError{Code: "ClientException", Message: "Unable to describe task definition."}
+	}
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: latest}, nil
+}
+
+// Cluster is an EKS cluster with its managed node groups.
+type Cluster struct {
+	Cluster    ekstypes.Cluster
+	Nodegroups []ekstypes.Nodegroup
+}
+
+// EKS serves a fixed set of clusters.
+type EKS struct {
+	Clusters []Cluster
+}
+
+func (f *EKS) cluster(name *string) (*Cluster, error) {
+	for i := range f.Clusters {
+		if aws.ToString(f.Clusters[i].Cluster.Name) == aws.ToString(name) {
+			return &f.Clusters[i], nil
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "cluster not found"}
+}
+
+func (f *EKS) ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+	out := &eks.ListClustersOutput{}
+	for _, c := range f.Clusters {
+		out.Clusters = append(out.Clusters, aws.ToString(c.Cluster.Name))
+	}
+	return out, nil
+}
+
+func (f *EKS) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	c, err := f.cluster(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	cluster := c.Cluster
+	return &eks.DescribeClusterOutput{Cluster: &cluster}, nil
+}
+
+func (f *EKS) ListNodegroups(ctx context.Context, params *eks.ListNodegroupsInput, optFns ...func(*eks.Options)) (*eks.ListNodegroupsOutput, error) {
+	c, err := f.cluster(params.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	out := &eks.ListNodegroupsOutput{}
+	for _, ng := range c.Nodegroups {
+		out.Nodegroups = append(out.Nodegroups, aws.ToString(ng.NodegroupName))
+	}
+	return out, nil
+}
+
+func (f *EKS) DescribeNodegroup(ctx context.Context, params *eks.DescribeNodegroupInput, optFns ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error) {
+	c, err := f.cluster(params.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, ng := range c.Nodegroups {
+		if aws.ToString(ng.NodegroupName) == aws.ToString(params.NodegroupName) {
+			return &eks.DescribeNodegroupOutput{Nodegroup: &ng}, nil
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "node group not found"}
+}
+
+// AutoScaling serves a fixed set of Auto Scaling groups and launch
+// configurations.
+type AutoScaling struct {
+	Groups               []astypes.AutoScalingGroup
+	LaunchConfigurations []astypes.LaunchConfiguration
+}
+
+func (f *AutoScaling) DescribeAutoScalingGroups(ctx context.Context, params *autoscaling.DescribeAutoScalingGroupsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	return &autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: f.Groups}, nil
+}
+
+// DescribeLaunchConfigurations returns the configurations named in
+// LaunchConfigurationNames, or all of them when none are given.
+func (f *AutoScaling) DescribeLaunchConfigurations(ctx context.Context, params *autoscaling.DescribeLaunchConfigurationsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeLaunchConfigurationsOutput, error) {
+	var configs []astypes.LaunchConfiguration
+	for _, lc := range f.LaunchConfigurations {
+		if len(params.LaunchConfigurationNames) > 0 && !slices.Contains(params.LaunchConfigurationNames, aws.ToString(lc.LaunchConfigurationName)) {
+			continue
+		}
+		configs = append(configs, lc)
+	}
+	return &autoscaling.DescribeLaunchConfigurationsOutput{LaunchConfigurations: configs}, nil
+}
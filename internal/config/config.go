@@ -1,20 +1,30 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
 
+const fileName = "ghostweights.yaml"
+
 type Config struct {
-	Regions      []string            `yaml:"regions"`
-	Exclude      ExcludeConfig       `yaml:"exclude"`
-	CustomPorts  []CustomPort        `yaml:"custom_ports"`
-	DeepScan     bool                `yaml:"deep_scan"`
-	OutputFormat string              `yaml:"output_format"`
-	MinRisk      string              `yaml:"min_risk"`
-	Slack        SlackConfig         `yaml:"slack"`
+	Regions      []string      `yaml:"regions"`
+	Exclude      ExcludeConfig `yaml:"exclude"`
+	CustomPorts  []CustomPort  `yaml:"custom_ports"`
+	DeepScan     bool          `yaml:"deep_scan"`
+	OutputFormat string        `yaml:"output_format"`
+	MinRisk      string        `yaml:"min_risk"`
+	Slack        SlackConfig   `yaml:"slack"`
+	Concurrency  int           `yaml:"concurrency"`
+	Probe        bool          `yaml:"probe"`
+	Kubeconfig   string        `yaml:"kubeconfig"`
+
+	IncludeRegions []string `yaml:"include_regions"`
+	ExcludeRegions []string `yaml:"exclude_regions"`
 }
 
 type ExcludeConfig struct {
@@ -30,14 +40,15 @@ type CustomPort struct {
 }
 
 type SlackConfig struct {
-	WebhookURL string `yaml:"webhook_url"`
-	Channel    string `yaml:"channel"`
+	WebhookURL  string `yaml:"webhook_url"`
+	Channel     string `yaml:"channel"`
+	MinRisk     string `yaml:"min_risk"`
+	NotifyClean bool   `yaml:"notify_clean"`
 }
 
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
@@ -49,6 +60,54 @@ func LoadConfig(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// DefaultPaths returns the locations searched for a config file when no
+// explicit path is given, in priority order.
+func DefaultPaths() []string {
+	paths := []string{fileName}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		paths = append(paths, filepath.Join(configHome, "ghostweights", "config.yaml"))
+	}
+
+	return paths
+}
+
+// Resolve loads the config at path, or the first existing file from
+// DefaultPaths when path is empty. It returns an empty Config and no path
+// when nothing was found.
+func Resolve(path string) (*Config, string, error) {
+	if path != "" {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return cfg, path, cfg.Validate()
+	}
+
+	for _, candidate := range DefaultPaths() {
+		if _, err := os.Stat(candidate); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, "", fmt.Errorf("failed to stat config file: %w", err)
+		}
+
+		cfg, err := LoadConfig(candidate)
+		if err != nil {
+			return nil, "", err
+		}
+		return cfg, candidate, cfg.Validate()
+	}
+
+	return &Config{}, "", nil
+}
+
 func (c *Config) Validate() error {
 	if c.OutputFormat != "" && c.OutputFormat != "table" && c.OutputFormat != "json" && c.OutputFormat != "csv" {
 		return fmt.Errorf("invalid output_format: %s", c.OutputFormat)
@@ -59,5 +118,22 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid min_risk: %s", c.MinRisk)
 	}
 
+	if c.Concurrency < 0 {
+		return fmt.Errorf("invalid concurrency: %d", c.Concurrency)
+	}
+
+	if c.Slack.MinRisk != "" && !validRisks[c.Slack.MinRisk] {
+		return fmt.Errorf("invalid slack.min_risk: %s", c.Slack.MinRisk)
+	}
+
+	for _, p := range c.CustomPorts {
+		if p.Port < 1 || p.Port > 65535 {
+			return fmt.Errorf("invalid custom_ports entry: port %d out of range", p.Port)
+		}
+		if p.Risk != "" && !validRisks[p.Risk] {
+			return fmt.Errorf("invalid custom_ports entry: port %d has invalid risk %s", p.Port, p.Risk)
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
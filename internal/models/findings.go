@@ -9,6 +9,30 @@ const (
 	RiskLow      RiskLevel = "LOW"
 )
 
+// Rank orders risk levels from LOW (1) to CRITICAL (4). Unknown levels rank 0.
+func (r RiskLevel) Rank() int {
+	switch r {
+	case RiskCritical:
+		return 4
+	case RiskHigh:
+		return 3
+	case RiskMedium:
+		return 2
+	case RiskLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtMost returns r capped at max.
+func (r RiskLevel) AtMost(max RiskLevel) RiskLevel {
+	if r.Rank() > max.Rank() {
+		return max
+	}
+	return r
+}
+
 type Finding struct {
 	InstanceID  string    `json:"instance_id"`
 	Region      string    `json:"region"`
@@ -127,6 +127,33 @@ func (s *SlackNotifier) sendCleanReport() error {
 	return s.sendMessage(msg)
 }
 
+// SendIncompleteReport posts a warning for a scan that found nothing but
+// couldn't cover everything, in place of a clean report.
+func (s *SlackNotifier) SendIncompleteReport(errs []error) error {
+	text := ""
+	for i, err := range errs {
+		if i >= 5 {
+			text += fmt.Sprintf("\n_...and %d more_", len(errs)-5)
+			break
+		}
+		text += fmt.Sprintf("• %s\n", err)
+	}
+
+	msg := slackMessage{
+		Channel:   s.Channel,
+		Username:  "GhostWeights",
+		IconEmoji: ":warning:",
+		Text:      "⚠️ *GhostWeights Scan Incomplete*\nNo Shadow AI artifacts found, but parts of the scan failed. This is not a clean result.",
+		Attachments: []slackAttachment{{
+			Color: "warning",
+			Title: fmt.Sprintf("Scan Errors (%d)", len(errs)),
+			Text:  text,
+		}},
+	}
+
+	return s.sendMessage(msg)
+}
+
 func (s *SlackNotifier) sendMessage(msg slackMessage) error {
 	jsonData, err := json.Marshal(msg)
 	if err != nil {
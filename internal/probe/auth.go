@@ -0,0 +1,147 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Impact is what an anonymous caller can do with an open endpoint.
+type Impact int
+
+const (
+	// ImpactDisclosure means data can be read.
+	ImpactDisclosure Impact = iota + 1
+	// ImpactAbuse means compute, models or stored state can be used or changed.
+	ImpactAbuse
+	// ImpactExec means arbitrary code can be run on the host.
+	ImpactExec
+)
+
+// AuthStatus summarises an endpoint's authentication requirements.
+type AuthStatus int
+
+const (
+	// AuthUnknown means no check applied or none gave a clear answer.
+	AuthUnknown AuthStatus = iota
+	// AuthRequired means the endpoint rejected anonymous requests.
+	AuthRequired
+	// AuthNone means at least one sensitive API answered anonymously.
+	AuthNone
+)
+
+// Exposure is an API that answered without credentials.
+type Exposure struct {
+	// Title names the exposure, e.g. "Unauthenticated Ray Job API (RCE)".
+	Title string
+	// Description explains what an anonymous caller can do.
+	Description string
+	Impact      Impact
+	// Evidence records the request that proved it.
+	Evidence string
+}
+
+// authCheck is one non-mutating request that tells whether a sensitive API
+// is open. Only GET requests are sent; nothing is created, pulled or run.
+type authCheck struct {
+	Path        string
+	Title       string
+	Description string
+	Impact      Impact
+	Open        func(resp *Response) bool
+}
+
+// authChecks are keyed by the Service name set by Fingerprint.
+var authChecks = map[string][]authCheck{
+	"Jupyter": {{
+		Path:        "/api/kernels",
+		Title:       "Unauthenticated Jupyter Server (RCE)",
+		Description: "Anyone can start kernels and run code on the host",
+		Impact:      ImpactExec,
+		Open:        jsonArray,
+	}},
+	"Ray": {{
+		Path:        "/api/jobs/",
+		Title:       "Unauthenticated Ray Job API (RCE)",
+		Description: "Anyone can submit jobs that run arbitrary code on the cluster",
+		Impact:      ImpactExec,
+		Open:        jsonArray,
+	}},
+	"Ollama": {{
+		Path:        "/api/tags",
+		Title:       "Unauthenticated Ollama API",
+		Description: "Anyone can run inference and pull, copy or delete models",
+		Impact:      ImpactAbuse,
+		Open:        statusOK,
+	}},
+	"OpenAI-compatible API": {{
+		Path:        "/v1/models",
+		Title:       "Unauthenticated LLM Inference API",
+		Description: "Anyone can run inference against the served models",
+		Impact:      ImpactAbuse,
+		Open:        statusOK,
+	}},
+	"MLflow": {{
+		Path:        "/api/2.0/mlflow/registered-models/search?max_results=1",
+		Title:       "Unauthenticated MLflow Tracking Server",
+		Description: "Anyone can read and modify experiments, runs and registered models",
+		Impact:      ImpactAbuse,
+		Open:        statusOK,
+	}},
+	"Gradio": {{
+		Path:        "/config",
+		Title:       "Unauthenticated Gradio App",
+		Description: "Anyone can call the app's prediction endpoints",
+		Impact:      ImpactAbuse,
+		Open:        statusOK,
+	}},
+}
+
+// CheckAuth runs the auth checks for a confirmed result and records what
+// answered anonymously. Results that aren't confirmed are left unchanged.
+func (p *Prober) CheckAuth(ctx context.Context, host string, port int32, r *Result) error {
+	if !r.Confirmed() {
+		return nil
+	}
+
+	rejected := false
+	for _, check := range authChecks[r.Service] {
+		resp, err := p.get(ctx, r.Scheme, host, port, check.Path)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		switch {
+		case resp.Status == http.StatusUnauthorized || resp.Status == http.StatusForbidden:
+			rejected = true
+		case check.Open(resp):
+			r.Exposures = append(r.Exposures, Exposure{
+				Title:       check.Title,
+				Description: check.Description,
+				Impact:      check.Impact,
+				Evidence:    fmt.Sprintf("GET %s returned %d without credentials", check.Path, resp.Status),
+			})
+		}
+	}
+
+	switch {
+	case len(r.Exposures) > 0:
+		r.Auth = AuthNone
+	case rejected:
+		r.Auth = AuthRequired
+	}
+	return nil
+}
+
+func statusOK(resp *Response) bool {
+	return resp.Status == http.StatusOK
+}
+
+func jsonArray(resp *Response) bool {
+	var v []json.RawMessage
+	return resp.Status == http.StatusOK && resp.JSON(&v)
+}
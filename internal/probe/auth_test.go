@@ -0,0 +1,84 @@
+package probe
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		routes    map[string]func(http.ResponseWriter)
+		wantAuth  AuthStatus
+		wantTitle string
+		wantImp   Impact
+	}{
+		{
+			name: "ray job api open",
+			routes: map[string]func(http.ResponseWriter){
+				"/api/version": body(200, `{"ray_version":"2.9.0"}`),
+				"/api/jobs/":   body(200, `[]`),
+			},
+			wantAuth:  AuthNone,
+			wantTitle: "Unauthenticated Ray Job API (RCE)",
+			wantImp:   ImpactExec,
+		},
+		{
+			name: "jupyter without token",
+			routes: map[string]func(http.ResponseWriter){
+				"/api/status":  body(200, `{"started":"2024-01-01T00:00:00Z","kernels":1}`),
+				"/api/kernels": body(200, `[{"id":"k1"}]`),
+			},
+			wantAuth:  AuthNone,
+			wantTitle: "Unauthenticated Jupyter Server (RCE)",
+			wantImp:   ImpactExec,
+		},
+		{
+			name: "jupyter with token",
+			routes: map[string]func(http.ResponseWriter){
+				"/api/status":  body(403, `{}`, "Server", "TornadoServer/6.4"),
+				"/api/kernels": body(403, `{}`, "Server", "TornadoServer/6.4"),
+			},
+			wantAuth: AuthRequired,
+		},
+		{
+			name: "ollama",
+			routes: map[string]func(http.ResponseWriter){
+				"/api/tags": body(200, `{"models":[]}`),
+			},
+			wantAuth:  AuthNone,
+			wantTitle: "Unauthenticated Ollama API",
+			wantImp:   ImpactAbuse,
+		},
+	}
+
+	p := New(2 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port := serve(t, tt.routes)
+
+			r, err := p.Fingerprint(context.Background(), host, port)
+			if err != nil {
+				t.Fatalf("Fingerprint: %v", err)
+			}
+			if err := p.CheckAuth(context.Background(), host, port, r); err != nil {
+				t.Fatalf("CheckAuth: %v", err)
+			}
+
+			if r.Auth != tt.wantAuth {
+				t.Errorf("Auth = %d, want %d", r.Auth, tt.wantAuth)
+			}
+			if tt.wantTitle == "" {
+				if len(r.Exposures) != 0 {
+					t.Errorf("Exposures = %+v, want none", r.Exposures)
+				}
+				return
+			}
+			if len(r.Exposures) != 1 || r.Exposures[0].Title != tt.wantTitle || r.Exposures[0].Impact != tt.wantImp {
+				t.Errorf("Exposures = %+v, want %q", r.Exposures, tt.wantTitle)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Package probe sends safe, read-only HTTP requests to exposed AI endpoints
+// to find out what is actually listening.
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBody caps how much of a response is read.
+const maxBody = 256 * 1024
+
+// Result describes what a probe found on host:port.
+type Result struct {
+	// Reachable is true when anything answered over HTTP(S).
+	Reachable bool
+	// Service is the recognised AI service, empty when unrecognised.
+	Service string
+	// Scheme is "http" or "https", whichever answered.
+	Scheme string
+	// Path is the request that produced the fingerprint.
+	Path string
+	// Models lists served model names, when the service exposes them.
+	Models []string
+	// Detail is a short human-readable summary for finding evidence.
+	Detail string
+	// Err is why nothing answered, when Reachable is false.
+	Err error
+
+	// Auth and Exposures are filled in by CheckAuth.
+	Auth      AuthStatus
+	Exposures []Exposure
+}
+
+// Confirmed reports whether an AI service was positively identified.
+func (r *Result) Confirmed() bool {
+	return r != nil && r.Service != ""
+}
+
+// Prober runs fingerprint checks over HTTP.
+type Prober struct {
+	Client *http.Client
+}
+
+// New returns a Prober whose requests time out after timeout. Certificate
+// verification is disabled because exposed endpoints rarely have valid
+// certificates and only read-only requests are sent.
+func New(timeout time.Duration) *Prober {
+	return &Prober{
+		Client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				DisableKeepAlives:   true,
+				TLSHandshakeTimeout: timeout,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Response is a captured HTTP response handed to fingerprint matchers.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the body into v.
+func (r *Response) JSON(v any) bool {
+	return json.Unmarshal(r.Body, v) == nil
+}
+
+// fingerprint is one read-only check for a known AI service.
+type fingerprint struct {
+	Service string
+	Port    int32
+	Path    string
+	Match   func(resp *Response) (ok bool, models []string, detail string)
+}
+
+// fingerprints are tried in order, with those whose Port matches the target
+// port first.
+var fingerprints = []fingerprint{
+	{Service: "Ollama", Port: 11434, Path: "/api/tags", Match: matchOllama},
+	{Service: "OpenAI-compatible API", Port: 8000, Path: "/v1/models", Match: matchOpenAI},
+	{Service: "Jupyter", Port: 8888, Path: "/api/status", Match: matchJupyter},
+	{Service: "Ray", Port: 8265, Path: "/api/version", Match: matchRay},
+	{Service: "Gradio", Port: 7860, Path: "/config", Match: matchGradio},
+	{Service: "MLflow", Port: 5000, Path: "/api/2.0/mlflow/experiments/search?max_results=1", Match: matchMLflow},
+	{Service: "Streamlit", Port: 8501, Path: "/_stcore/health", Match: matchStreamlit},
+}
+
+// Fingerprint identifies the service on host:port. A nil error with
+// Reachable false means nothing answered.
+func (p *Prober) Fingerprint(ctx context.Context, host string, port int32) (*Result, error) {
+	scheme, err := p.detectScheme(ctx, host, port)
+	if err != nil {
+		return &Result{Err: err}, nil
+	}
+
+	result := &Result{Reachable: true, Scheme: scheme}
+	for _, fp := range orderedFingerprints(port) {
+		resp, err := p.get(ctx, scheme, host, port, fp.Path)
+		if err != nil {
+			continue
+		}
+		if ok, models, detail := fp.Match(resp); ok {
+			result.Service = fp.Service
+			result.Path = fp.Path
+			result.Models = models
+			result.Detail = detail
+			return result, nil
+		}
+	}
+
+	return result, nil
+}
+
+// detectScheme returns the first of http and https that answers at all.
+func (p *Prober) detectScheme(ctx context.Context, host string, port int32) (string, error) {
+	var lastErr error
+	for _, scheme := range []string{"http", "https"} {
+		if _, err := p.get(ctx, scheme, host, port, "/"); err != nil {
+			lastErr = err
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				// A filtered port times out on both schemes; don't wait twice.
+				return "", err
+			}
+			continue
+		}
+		return scheme, nil
+	}
+	return "", lastErr
+}
+
+func (p *Prober) get(ctx context.Context, scheme, host string, port int32, path string) (*Response, error) {
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, fmt.Sprint(port)), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "GhostWeights/1.0 (security audit)")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{Status: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+func orderedFingerprints(port int32) []fingerprint {
+	ordered := make([]fingerprint, 0, len(fingerprints))
+	for _, fp := range fingerprints {
+		if fp.Port == port {
+			ordered = append(ordered, fp)
+		}
+	}
+	for _, fp := range fingerprints {
+		if fp.Port != port {
+			ordered = append(ordered, fp)
+		}
+	}
+	return ordered
+}
+
+func matchOllama(resp *Response) (bool, []string, string) {
+	var body struct {
+		Models *[]struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if resp.Status != http.StatusOK || !resp.JSON(&body) || body.Models == nil {
+		return false, nil, ""
+	}
+
+	var models []string
+	for _, m := range *body.Models {
+		models = append(models, m.Name)
+	}
+	return true, models, fmt.Sprintf("GET /api/tags listed %d models", len(models))
+}
+
+func matchOpenAI(resp *Response) (bool, []string, string) {
+	var body struct {
+		Object string `json:"object"`
+		Data   []struct {
+			ID      string `json:"id"`
+			OwnedBy string `json:"owned_by"`
+		} `json:"data"`
+	}
+	if resp.Status != http.StatusOK || !resp.JSON(&body) || body.Object != "list" {
+		return false, nil, ""
+	}
+
+	var models []string
+	for _, m := range body.Data {
+		models = append(models, m.ID)
+	}
+	return true, models, fmt.Sprintf("GET /v1/models listed %d models", len(models))
+}
+
+func matchJupyter(resp *Response) (bool, []string, string) {
+	if resp.Status == http.StatusOK {
+		var body struct {
+			Started *string `json:"started"`
+			Kernels *int    `json:"kernels"`
+		}
+		if resp.JSON(&body) && body.Started != nil && body.Kernels != nil {
+			return true, nil, fmt.Sprintf("GET /api/status answered (%d kernels)", *body.Kernels)
+		}
+	}
+	if resp.Status == http.StatusForbidden && strings.Contains(resp.Header.Get("Server"), "Tornado") {
+		return true, nil, "GET /api/status requires a token"
+	}
+	return false, nil, ""
+}
+
+func matchRay(resp *Response) (bool, []string, string) {
+	var body struct {
+		RayVersion string `json:"ray_version"`
+	}
+	if resp.Status != http.StatusOK || !resp.JSON(&body) || body.RayVersion == "" {
+		return false, nil, ""
+	}
+	return true, nil, fmt.Sprintf("Ray %s dashboard API", body.RayVersion)
+}
+
+func matchGradio(resp *Response) (bool, []string, string) {
+	var body struct {
+		Version    string            `json:"version"`
+		Components []json.RawMessage `json:"components"`
+	}
+	if resp.Status != http.StatusOK || !resp.JSON(&body) || body.Components == nil {
+		return false, nil, ""
+	}
+	return true, nil, fmt.Sprintf("Gradio %s app with %d components", body.Version, len(body.Components))
+}
+
+func matchMLflow(resp *Response) (bool, []string, string) {
+	var body struct {
+		Experiments *[]struct {
+			Name string `json:"name"`
+		} `json:"experiments"`
+	}
+	if resp.Status != http.StatusOK || !resp.JSON(&body) || body.Experiments == nil {
+		return false, nil, ""
+	}
+	return true, nil, "MLflow tracking API answered experiments/search"
+}
+
+func matchStreamlit(resp *Response) (bool, []string, string) {
+	if resp.Status != http.StatusOK || strings.TrimSpace(string(resp.Body)) != "ok" {
+		return false, nil, ""
+	}
+	return true, nil, "Streamlit health endpoint answered"
+}
@@ -0,0 +1,140 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// serve starts a server answering path with status and body, and returns
+// its host and port.
+func serve(t *testing.T, routes map[string]func(w http.ResponseWriter)) (string, int32) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h, ok := routes[r.URL.Path]; ok {
+			h(w)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	host, portStr, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, int32(port)
+}
+
+func body(status int, s string, header ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(s))
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name       string
+		routes     map[string]func(w http.ResponseWriter)
+		wantSvc    string
+		wantModels []string
+	}{
+		{
+			name:       "ollama",
+			routes:     map[string]func(http.ResponseWriter){"/api/tags": body(200, `{"models":[{"name":"llama3:8b"},{"name":"mistral"}]}`)},
+			wantSvc:    "Ollama",
+			wantModels: []string{"llama3:8b", "mistral"},
+		},
+		{
+			name:       "openai compatible",
+			routes:     map[string]func(http.ResponseWriter){"/v1/models": body(200, `{"object":"list","data":[{"id":"meta-llama/Llama-3-8b"}]}`)},
+			wantSvc:    "OpenAI-compatible API",
+			wantModels: []string{"meta-llama/Llama-3-8b"},
+		},
+		{
+			name:    "jupyter with token",
+			routes:  map[string]func(http.ResponseWriter){"/api/status": body(403, `{"message":"Forbidden"}`, "Server", "TornadoServer/6.4")},
+			wantSvc: "Jupyter",
+		},
+		{
+			name:    "ray",
+			routes:  map[string]func(http.ResponseWriter){"/api/version": body(200, `{"ray_version":"2.9.0"}`)},
+			wantSvc: "Ray",
+		},
+		{
+			name:    "streamlit",
+			routes:  map[string]func(http.ResponseWriter){"/_stcore/health": body(200, "ok")},
+			wantSvc: "Streamlit",
+		},
+		{
+			name:   "unrecognised",
+			routes: map[string]func(http.ResponseWriter){"/": body(200, "<html>nginx</html>")},
+		},
+	}
+
+	p := New(2 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port := serve(t, tt.routes)
+
+			got, err := p.Fingerprint(context.Background(), host, port)
+			if err != nil {
+				t.Fatalf("Fingerprint: %v", err)
+			}
+			if !got.Reachable || got.Scheme != "http" {
+				t.Fatalf("Reachable = %v, Scheme = %q", got.Reachable, got.Scheme)
+			}
+			if got.Service != tt.wantSvc {
+				t.Errorf("Service = %q, want %q", got.Service, tt.wantSvc)
+			}
+			if !reflect.DeepEqual(got.Models, tt.wantModels) {
+				t.Errorf("Models = %v, want %v", got.Models, tt.wantModels)
+			}
+		})
+	}
+}
+
+func TestFingerprintUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().(*net.TCPAddr)
+	l.Close()
+
+	got, err := New(time.Second).Fingerprint(context.Background(), "127.0.0.1", int32(addr.Port))
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if got.Reachable || got.Confirmed() {
+		t.Errorf("got %+v, want unreachable", got)
+	}
+}
+
+func TestFingerprintCancelled(t *testing.T) {
+	host, port := serve(t, map[string]func(w http.ResponseWriter){
+		"/": func(w http.ResponseWriter) { w.Write([]byte("ok")) },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err := New(time.Second).Fingerprint(ctx, host, port)
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if got.Reachable || !errors.Is(got.Err, context.Canceled) {
+		t.Errorf("got %+v, want unreachable with context.Canceled", got)
+	}
+}
@@ -0,0 +1,307 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// aiAMI is a family of public AMIs that ship an AI stack.
+type aiAMI struct {
+	Name string
+	// Owners are the owner aliases or account IDs that publish the family.
+	// Public images from anyone else only match through a private lineage.
+	Owners []string
+	// Pattern is matched against the image name and description.
+	Pattern *regexp.Regexp
+	Risk    models.RiskLevel
+}
+
+// aiAMIs is the AMI catalogue, most specific first. Marketplace images are
+// all owned by aws-marketplace, so the pattern does the work there.
+var aiAMIs = []aiAMI{
+	{
+		Name:    "Marketplace LLM AMI",
+		Owners:  []string{"aws-marketplace", "679593333241"},
+		Pattern: regexp.MustCompile(`(?i)\b(ollama|vllm|llama ?[234]?|mistral|mixtral|deepseek|qwen|open ?webui|localai|privategpt|h2ogpt|gpt4all|text generation inference|llm)\b`),
+		Risk:    models.RiskHigh,
+	},
+	{
+		Name:    "NVIDIA NGC AMI",
+		Owners:  []string{"aws-marketplace", "679593333241"},
+		Pattern: regexp.MustCompile(`(?i)\bNGC\b|nvidia (gpu|ai)[- ]optimized|nvidia ai enterprise|nvidia riva|nvidia nemo`),
+		Risk:    models.RiskMedium,
+	},
+	{
+		Name:    "Hugging Face AMI",
+		Owners:  []string{"aws-marketplace", "679593333241"},
+		Pattern: regexp.MustCompile(`(?i)hugging ?face`),
+		Risk:    models.RiskMedium,
+	},
+	{
+		Name:    "AWS Deep Learning AMI",
+		Owners:  []string{"amazon", "898082745236"},
+		Pattern: regexp.MustCompile(`(?i)\bdeep learning\b|\bDLAMI\b`),
+		Risk:    models.RiskMedium,
+	},
+}
+
+// matchAIAMI returns the catalogue entry a public image belongs to.
+func matchAIAMI(img types.Image) (aiAMI, bool) {
+	owner := []string{aws.ToString(img.ImageOwnerAlias), aws.ToString(img.OwnerId)}
+	text := aws.ToString(img.Name) + " " + aws.ToString(img.Description)
+	for _, a := range aiAMIs {
+		if slices.ContainsFunc(a.Owners, func(o string) bool { return slices.Contains(owner, o) }) && a.Pattern.MatchString(text) {
+			return a, true
+		}
+	}
+	return aiAMI{}, false
+}
+
+// mentionAIAMI returns the catalogue entry that text, such as the name of a
+// private image, refers to. Owners are ignored.
+func mentionAIAMI(text string) (aiAMI, bool) {
+	for _, a := range aiAMIs {
+		if a.Pattern.MatchString(text) {
+			return a, true
+		}
+	}
+	return aiAMI{}, false
+}
+
+func isPrivateImage(img types.Image) bool {
+	return !aws.ToBool(img.Public) && img.ImageOwnerAlias == nil
+}
+
+// amiLineageDepth bounds how many parents are followed from a private AMI.
+const amiLineageDepth = 3
+
+// maxImageFilterValues keeps image-id filters well under the API's limit on
+// filter values.
+const maxImageFilterValues = 100
+
+// parentAMITags are tags, compared case-insensitively, that Packer, EC2
+// Image Builder and in-house pipelines use to record the base image.
+var parentAMITags = []string{
+	"source_ami", "sourceami", "source_ami_id", "source_ami_name",
+	"base_ami", "baseami", "base_ami_id", "base_ami_name",
+	"parent_ami", "parent_image", "base_image",
+}
+
+// amiLineage is what an instance's AMI was matched to.
+type amiLineage struct {
+	Image types.Image
+	Match aiAMI
+	// Base is the catalogue image a private AMI was built from, or nil when
+	// the match came from the AMI's own name, description or tags.
+	Base *types.Image
+	// Mention is the text the match came from when Base is nil and the AMI
+	// is private.
+	Mention string
+}
+
+// ScanAMILineage flags instances launched from Deep Learning AMIs, NVIDIA
+// NGC images or marketplace LLM images, and from private AMIs built from
+// them. It needs no SSM agent.
+func (s *Scanner) ScanAMILineage(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var ids []string
+	for _, inst := range target.Instances {
+		if id := aws.ToString(inst.ImageId); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Resolving %d AMIs...", len(ids)))
+	images := map[string]*types.Image{}
+	if err := s.loadImages(ctx, images, ids); err != nil {
+		return nil, fmt.Errorf("failed to describe AMIs: %w", err)
+	}
+
+	lineages := map[string]*amiLineage{}
+	for _, id := range ids {
+		if img := images[id]; img != nil {
+			lineages[id] = s.amiLineage(ctx, images, *img)
+		}
+	}
+
+	var findings []models.Finding
+	for _, inst := range target.Instances {
+		l := lineages[aws.ToString(inst.ImageId)]
+		if l == nil {
+			continue
+		}
+		f := models.Finding{
+			InstanceID: aws.ToString(inst.InstanceId),
+			Region:     target.Region,
+			PublicIP:   getPublicIP(inst),
+			PrivateIP:  getPrivateIP(inst),
+			NameTag:    getNameTag(inst.Tags),
+			Risk:       l.Match.Risk,
+			Service:    "AI AMI",
+		}
+		ami := describeImage(l.Image)
+		switch {
+		case !isPrivateImage(l.Image):
+			f.Description = "Launched from " + l.Match.Name
+			f.Evidence = fmt.Sprintf("%s, owner %s", ami, imageOwner(l.Image))
+		case l.Base != nil:
+			f.Service = "Custom AI AMI"
+			f.Description = "Launched from a private AMI built from an AI base image"
+			f.Evidence = fmt.Sprintf("%s built from %s (%s, owner %s)", ami, describeImage(*l.Base), l.Match.Name, imageOwner(*l.Base))
+		default:
+			f.Service = "Custom AI AMI"
+			f.Description = "Launched from a private AMI that names an AI base image"
+			f.Evidence = fmt.Sprintf("%s refers to %s: %s", ami, l.Match.Name, truncate(l.Mention, 120))
+		}
+		findings = append(findings, f)
+	}
+
+	return findings, nil
+}
+
+// amiLineage matches a public image against the catalogue, and follows a
+// private image up through copies, parent AMI tags and the instances it was
+// created from until it reaches one.
+func (s *Scanner) amiLineage(ctx context.Context, images map[string]*types.Image, img types.Image) *amiLineage {
+	if !isPrivateImage(img) {
+		if m, ok := matchAIAMI(img); ok {
+			return &amiLineage{Image: img, Match: m}
+		}
+		return nil
+	}
+
+	var mention *amiLineage
+	seen := map[string]bool{aws.ToString(img.ImageId): true}
+	current := []types.Image{img}
+	for depth := 0; depth < amiLineageDepth && len(current) > 0; depth++ {
+		var parents []string
+		for _, c := range current {
+			ids, texts := s.imageParents(ctx, c)
+			for _, id := range ids {
+				if !seen[id] {
+					seen[id] = true
+					parents = append(parents, id)
+				}
+			}
+			if mention != nil {
+				continue
+			}
+			for _, t := range append([]string{aws.ToString(c.Name), aws.ToString(c.Description)}, texts...) {
+				if m, ok := mentionAIAMI(t); ok {
+					mention = &amiLineage{Image: img, Match: m, Mention: t}
+					break
+				}
+			}
+		}
+		if len(parents) == 0 {
+			break
+		}
+		if err := s.loadImages(ctx, images, parents); err != nil {
+			log.Printf("WARNING: Failed to resolve parents of AMI %s: %v", aws.ToString(img.ImageId), err)
+			break
+		}
+
+		current = nil
+		for _, id := range parents {
+			p := images[id]
+			if p == nil {
+				continue
+			}
+			if !isPrivateImage(*p) {
+				if m, ok := matchAIAMI(*p); ok {
+					return &amiLineage{Image: img, Match: m, Base: p}
+				}
+				continue
+			}
+			current = append(current, *p)
+		}
+	}
+	return mention
+}
+
+// imageParents returns the AMI IDs an image was derived from, and any
+// parent names recorded in its tags.
+func (s *Scanner) imageParents(ctx context.Context, img types.Image) (ids, names []string) {
+	if id := aws.ToString(img.SourceImageId); id != "" {
+		if r := aws.ToString(img.SourceImageRegion); r == "" || r == s.Client.Region {
+			ids = append(ids, id)
+		}
+	}
+	for _, tag := range img.Tags {
+		if !slices.Contains(parentAMITags, strings.ToLower(aws.ToString(tag.Key))) {
+			continue
+		}
+		if v := aws.ToString(tag.Value); strings.HasPrefix(v, "ami-") {
+			ids = append(ids, v)
+		} else if v != "" {
+			names = append(names, v)
+		}
+	}
+	if id := aws.ToString(img.SourceInstanceId); id != "" {
+		out, err := s.Client.EC2.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{id}})
+		if err == nil {
+			for _, r := range out.Reservations {
+				for _, inst := range r.Instances {
+					if parent := aws.ToString(inst.ImageId); parent != "" {
+						ids = append(ids, parent)
+					}
+				}
+			}
+		}
+	}
+	return ids, names
+}
+
+// loadImages describes the AMIs in ids that aren't in images yet. AMIs that
+// no longer exist or aren't visible to the account are stored as nil.
+func (s *Scanner) loadImages(ctx context.Context, images map[string]*types.Image, ids []string) error {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := images[id]; !ok {
+			missing = append(missing, id)
+			images[id] = nil
+		}
+	}
+
+	// The image-id filter, unlike ImageIds, doesn't fail the whole call
+	// when one AMI has been deregistered.
+	for chunk := range slices.Chunk(missing, maxImageFilterValues) {
+		paginator := ec2.NewDescribeImagesPaginator(s.Client.EC2, &ec2.DescribeImagesInput{
+			Filters:           []types.Filter{{Name: aws.String("image-id"), Values: chunk}},
+			IncludeDeprecated: aws.Bool(true),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return err
+			}
+			for i := range page.Images {
+				images[aws.ToString(page.Images[i].ImageId)] = &page.Images[i]
+			}
+		}
+	}
+	return nil
+}
+
+func describeImage(img types.Image) string {
+	return fmt.Sprintf("AMI %s %q", aws.ToString(img.ImageId), aws.ToString(img.Name))
+}
+
+func imageOwner(img types.Image) string {
+	if alias := aws.ToString(img.ImageOwnerAlias); alias != "" {
+		return alias
+	}
+	return aws.ToString(img.OwnerId)
+}
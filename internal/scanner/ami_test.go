@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func amiInstance(id, imageID string) types.Instance {
+	inst := testInstance(id)
+	inst.ImageId = aws.String(imageID)
+	return inst
+}
+
+func publicImage(id, owner, name string) types.Image {
+	return types.Image{
+		ImageId:         aws.String(id),
+		ImageOwnerAlias: aws.String(owner),
+		OwnerId:         aws.String("000000000000"),
+		Name:            aws.String(name),
+		Public:          aws.Bool(true),
+	}
+}
+
+func privateImage(id, name string) types.Image {
+	return types.Image{
+		ImageId: aws.String(id),
+		OwnerId: aws.String("111111111111"),
+		Name:    aws.String(name),
+	}
+}
+
+func TestScanAMILineage(t *testing.T) {
+	copied := privateImage("ami-copy", "golden-base")
+	copied.SourceImageId = aws.String("ami-dl2")
+	packer := privateImage("ami-packer", "inference-2026-09")
+	packer.Tags = []types.Tag{{Key: aws.String("Source_AMI"), Value: aws.String("ami-copy")}}
+	snapshot := privateImage("ami-snapshot", "trainer-snapshot")
+	snapshot.SourceInstanceId = aws.String("i-builder")
+	named := privateImage("ami-named", "team-base")
+	named.Description = aws.String("Deep Learning AMI GPU PyTorch with our agents")
+
+	ec2 := &fake.EC2{
+		Images: []types.Image{
+			publicImage("ami-dl", "amazon", "Deep Learning OSS Nvidia Driver AMI GPU PyTorch 2.3 (Ubuntu 22.04) 20240611"),
+			publicImage("ami-dl2", "amazon", "Deep Learning Base OSS Nvidia Driver GPU AMI (Amazon Linux 2023)"),
+			publicImage("ami-ollama", "aws-marketplace", "Ollama on Ubuntu 22.04-prod-abc123"),
+			publicImage("ami-ngc", "aws-marketplace", "NVIDIA GPU-Optimized AMI 24.3.1-prod-xyz"),
+			publicImage("ami-al2023", "amazon", "al2023-ami-2023.5.20240624.0-kernel-6.1-x86_64"),
+			{ImageId: aws.String("ami-clone"), OwnerId: aws.String("222222222222"), Name: aws.String("Deep Learning AMI (clone)"), Public: aws.Bool(true)},
+			copied, packer, snapshot, named,
+		},
+		Instances: []types.Instance{amiInstance("i-builder", "ami-ngc")},
+	}
+	instances := []types.Instance{
+		amiInstance("i-dl", "ami-dl"),
+		amiInstance("i-ollama", "ami-ollama"),
+		amiInstance("i-packer", "ami-packer"),
+		amiInstance("i-snapshot", "ami-snapshot"),
+		amiInstance("i-named", "ami-named"),
+		amiInstance("i-web", "ami-al2023"),
+		amiInstance("i-clone", "ami-clone"),
+		amiInstance("i-gone", "ami-deregistered"),
+	}
+
+	scn := newTestScanner(ec2, nil, nil)
+	findings, err := scn.ScanAMILineage(context.Background(), &Target{Region: "us-east-1", Instances: instances})
+	if err != nil {
+		t.Fatalf("ScanAMILineage: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]struct {
+		risk     models.RiskLevel
+		evidence string
+	}{
+		{"i-dl", "AI AMI"}:              {models.RiskMedium, `AMI ami-dl "Deep Learning OSS Nvidia Driver AMI GPU PyTorch 2.3 (Ubuntu 22.04) 20240611", owner amazon`},
+		{"i-ollama", "AI AMI"}:          {models.RiskHigh, `AMI ami-ollama "Ollama on Ubuntu 22.04-prod-abc123", owner aws-marketplace`},
+		{"i-packer", "Custom AI AMI"}:   {models.RiskMedium, `AMI ami-packer "inference-2026-09" built from AMI ami-dl2`},
+		{"i-snapshot", "Custom AI AMI"}: {models.RiskMedium, `built from AMI ami-ngc "NVIDIA GPU-Optimized AMI 24.3.1-prod-xyz" (NVIDIA NGC AMI, owner aws-marketplace)`},
+		{"i-named", "Custom AI AMI"}:    {models.RiskMedium, `AMI ami-named "team-base" refers to AWS Deep Learning AMI`},
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, w := range want {
+		f := got[k]
+		if f.Risk != w.risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, f.Risk, w.risk)
+		}
+		if !strings.Contains(f.Evidence, w.evidence) {
+			t.Errorf("%s / %s: evidence = %q, want it to contain %q", k.resource, k.service, f.Evidence, w.evidence)
+		}
+	}
+}
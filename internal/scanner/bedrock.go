@@ -0,0 +1,398 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	agenttypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+)
+
+// ScanBedrock inventories Bedrock usage in the region: enabled foundation
+// models, custom models, provisioned throughput, guardrails, agents and
+// knowledge bases. Agents without a guardrail and knowledge bases fed from
+// public or unencrypted buckets are flagged, and so is a region that uses
+// Bedrock with model-invocation logging off. A check that fails is logged
+// and the others still run.
+func (s *Scanner) ScanBedrock(ctx context.Context, target *Target) ([]models.Finding, error) {
+	// usage marks checks whose resources show Bedrock is actually in use.
+	// Model access and guardrails don't: models are often enabled
+	// wholesale and never called.
+	checks := []struct {
+		name  string
+		run   func(context.Context, *Target) ([]models.Finding, error)
+		usage bool
+	}{
+		{"model access", s.scanBedrockModelAccess, false},
+		{"custom models", s.scanBedrockCustomModels, true},
+		{"provisioned throughput", s.scanBedrockProvisioned, true},
+		{"guardrails", s.scanBedrockGuardrails, false},
+		{"agents", s.scanBedrockAgents, true},
+		{"knowledge bases", s.scanBedrockKnowledgeBases, true},
+	}
+
+	var findings []models.Finding
+	inUse := false
+	for _, c := range checks {
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking Bedrock %s in %s...", c.name, target.Region))
+		found, err := c.run(ctx, target)
+		if err != nil {
+			log.Printf("WARNING: Bedrock %s check failed in %s: %v", c.name, target.Region, err)
+			continue
+		}
+		findings = append(findings, found...)
+		inUse = inUse || c.usage && len(found) > 0
+	}
+
+	// Logging only matters where Bedrock is actually in use.
+	if !inUse {
+		return findings, nil
+	}
+	f, err := s.checkBedrockLogging(ctx, target)
+	if err != nil {
+		log.Printf("WARNING: Bedrock invocation logging check failed in %s: %v", target.Region, err)
+	} else if f != nil {
+		findings = append(findings, *f)
+	}
+	return findings, nil
+}
+
+// bedrockAvailabilityWorkers bounds concurrent GetFoundationModelAvailability
+// calls.
+const bedrockAvailabilityWorkers = 8
+
+func bedrockFinding(region, id, name string) models.Finding {
+	return models.Finding{InstanceID: id, Region: region, PublicIP: "N/A", PrivateIP: "N/A", NameTag: name}
+}
+
+// scanBedrockModelAccess reports the foundation models the account is
+// entitled and authorized to invoke as a single finding.
+func (s *Scanner) scanBedrockModelAccess(ctx context.Context, target *Target) ([]models.Finding, error) {
+	out, err := s.Client.Bedrock.ListFoundationModels(ctx, &bedrock.ListFoundationModelsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy models can't be newly invoked, so only active ones are
+	// checked, bedrockAvailabilityWorkers at a time.
+	var ids []string
+	for _, m := range out.ModelSummaries {
+		if m.ModelLifecycle != nil && m.ModelLifecycle.Status == bedrocktypes.FoundationModelLifecycleStatusLegacy {
+			continue
+		}
+		ids = append(ids, aws.ToString(m.ModelId))
+	}
+
+	accessible := make([]bool, len(ids))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(bedrockAvailabilityWorkers, len(ids)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				avail, err := s.Client.Bedrock.GetFoundationModelAvailability(ctx, &bedrock.GetFoundationModelAvailabilityInput{ModelId: aws.String(ids[i])})
+				if err != nil {
+					log.Printf("WARNING: Failed to get availability of %s: %v", ids[i], err)
+					continue
+				}
+				accessible[i] = modelAccessible(avail)
+			}
+		}()
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var enabled []string
+	for i, id := range ids {
+		if accessible[i] {
+			enabled = append(enabled, id)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil, nil
+	}
+
+	f := bedrockFinding(target.Region, "model-access", "Bedrock")
+	f.Risk = models.RiskLow
+	f.Service = "Bedrock Model Access"
+	f.Description = fmt.Sprintf("%d foundation models are enabled for invocation", len(enabled))
+	f.Evidence = "Enabled: " + strings.Join(enabled[:min(10, len(enabled))], ", ")
+	if len(enabled) > 10 {
+		f.Evidence += fmt.Sprintf(" and %d more", len(enabled)-10)
+	}
+	return []models.Finding{f}, nil
+}
+
+func modelAccessible(out *bedrock.GetFoundationModelAvailabilityOutput) bool {
+	if out.AgreementAvailability != nil && out.AgreementAvailability.Status != bedrocktypes.AgreementStatusAvailable {
+		return false
+	}
+	return out.AuthorizationStatus == bedrocktypes.AuthorizationStatusAuthorized &&
+		out.EntitlementAvailability == bedrocktypes.EntitlementAvailabilityAvailable
+}
+
+func (s *Scanner) scanBedrockCustomModels(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	paginator := bedrock.NewListCustomModelsPaginator(s.Client.Bedrock, &bedrock.ListCustomModelsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page.ModelSummaries {
+			name := aws.ToString(m.ModelName)
+			f := bedrockFinding(target.Region, name, name)
+			f.Risk = models.RiskLow
+			f.Service = "Bedrock Custom Model"
+			f.Description = "Custom model trained on account data"
+			f.Evidence = fmt.Sprintf("Custom model %s (%s of %s, %s)", name, m.CustomizationType, aws.ToString(m.BaseModelName), m.ModelStatus)
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanBedrockProvisioned(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	paginator := bedrock.NewListProvisionedModelThroughputsPaginator(s.Client.Bedrock, &bedrock.ListProvisionedModelThroughputsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.ProvisionedModelSummaries {
+			name := aws.ToString(p.ProvisionedModelName)
+			commitment := string(p.CommitmentDuration)
+			if commitment == "" {
+				commitment = "no commitment"
+			}
+
+			f := bedrockFinding(target.Region, name, name)
+			f.Risk = models.RiskLow
+			f.Service = "Bedrock Provisioned Throughput"
+			f.Description = "Dedicated model capacity billed by the hour"
+			f.Evidence = fmt.Sprintf("Provisioned throughput %s: %d model units of %s, %s, %s",
+				name, aws.ToInt32(p.ModelUnits), aws.ToString(p.ModelArn), commitment, p.Status)
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanBedrockGuardrails(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	paginator := bedrock.NewListGuardrailsPaginator(s.Client.Bedrock, &bedrock.ListGuardrailsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range page.Guardrails {
+			f := bedrockFinding(target.Region, aws.ToString(g.Id), aws.ToString(g.Name))
+			f.Risk = models.RiskLow
+			f.Service = "Bedrock Guardrail"
+			f.Description = "Guardrail available to filter model input and output"
+			f.Evidence = fmt.Sprintf("Guardrail %s (%s) version %s, %s", aws.ToString(g.Name), aws.ToString(g.Id), aws.ToString(g.Version), g.Status)
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanBedrockAgents(ctx context.Context, target *Target) ([]models.Finding, error) {
+	if s.Client.BedrockAgent == nil {
+		return nil, nil
+	}
+
+	var findings []models.Finding
+
+	paginator := bedrockagent.NewListAgentsPaginator(s.Client.BedrockAgent, &bedrockagent.ListAgentsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range page.AgentSummaries {
+			name := aws.ToString(a.AgentName)
+			f := bedrockFinding(target.Region, aws.ToString(a.AgentId), name)
+			where := fmt.Sprintf("Agent %s (%s, %s)", name, aws.ToString(a.AgentId), a.AgentStatus)
+
+			if g := a.GuardrailConfiguration; g != nil && aws.ToString(g.GuardrailIdentifier) != "" {
+				f.Risk = models.RiskLow
+				f.Service = "Bedrock Agent"
+				f.Description = "Agent with a guardrail attached"
+				f.Evidence = fmt.Sprintf("%s: guardrail %s version %s", where, aws.ToString(g.GuardrailIdentifier), aws.ToString(g.GuardrailVersion))
+			} else {
+				f.Risk = models.RiskHigh
+				f.Service = "Bedrock Agent Without Guardrail"
+				f.Description = "Agent prompts and responses are not filtered by a guardrail"
+				f.Evidence = where + ": no guardrail configured"
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+// scanBedrockKnowledgeBases checks the S3 data sources of each knowledge
+// base. A knowledge base with no risky source is reported once as inventory.
+func (s *Scanner) scanBedrockKnowledgeBases(ctx context.Context, target *Target) ([]models.Finding, error) {
+	if s.Client.BedrockAgent == nil {
+		return nil, nil
+	}
+
+	var findings []models.Finding
+	postures := map[string]*bucketPosture{}
+
+	paginator := bedrockagent.NewListKnowledgeBasesPaginator(s.Client.BedrockAgent, &bedrockagent.ListKnowledgeBasesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, kb := range page.KnowledgeBaseSummaries {
+			name := aws.ToString(kb.Name)
+			base := bedrockFinding(target.Region, aws.ToString(kb.KnowledgeBaseId), name)
+
+			sources, err := s.knowledgeBaseSources(ctx, kb)
+			if err != nil {
+				log.Printf("WARNING: Failed to list data sources of knowledge base %s: %v", name, err)
+				continue
+			}
+
+			flagged := false
+			var buckets []string
+			for _, ds := range sources {
+				bucket := dataSourceBucket(ds)
+				if bucket == "" {
+					continue
+				}
+				buckets = append(buckets, bucket)
+
+				posture, seen := postures[bucket]
+				if !seen && s.Client.S3 != nil {
+					if p, err := s.bucketPosture(ctx, bucket); err == nil {
+						posture = &p
+					}
+					postures[bucket] = posture
+				}
+				if posture == nil {
+					continue
+				}
+
+				where := fmt.Sprintf("Knowledge base %s data source %s reads s3://%s", name, aws.ToString(ds.Name), bucket)
+				if posture.Public {
+					f := base
+					f.Risk = models.RiskHigh
+					f.Service = "Bedrock Knowledge Base Public Source"
+					f.Description = "Knowledge base ingests a publicly accessible bucket; anyone able to write to it can poison answers"
+					f.Evidence = where + " (bucket is public)"
+					findings = append(findings, f)
+					flagged = true
+				}
+				if !posture.Encrypted {
+					f := base
+					f.Risk = models.RiskMedium
+					f.Service = "Bedrock Knowledge Base Unencrypted Source"
+					f.Description = "Knowledge base source documents are stored without default encryption"
+					f.Evidence = where + " (no default encryption)"
+					findings = append(findings, f)
+					flagged = true
+				}
+			}
+
+			if !flagged {
+				f := base
+				f.Risk = models.RiskLow
+				f.Service = "Bedrock Knowledge Base"
+				f.Description = "Knowledge base retrieving from account data"
+				f.Evidence = fmt.Sprintf("Knowledge base %s (%s), %d data sources", name, kb.Status, len(sources))
+				if len(buckets) > 0 {
+					f.Evidence += ": s3://" + strings.Join(buckets, ", s3://")
+				}
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) knowledgeBaseSources(ctx context.Context, kb agenttypes.KnowledgeBaseSummary) ([]agenttypes.DataSource, error) {
+	var sources []agenttypes.DataSource
+
+	paginator := bedrockagent.NewListDataSourcesPaginator(s.Client.BedrockAgent, &bedrockagent.ListDataSourcesInput{
+		KnowledgeBaseId: kb.KnowledgeBaseId,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range page.DataSourceSummaries {
+			out, err := s.Client.BedrockAgent.GetDataSource(ctx, &bedrockagent.GetDataSourceInput{
+				KnowledgeBaseId: kb.KnowledgeBaseId,
+				DataSourceId:    summary.DataSourceId,
+			})
+			if err != nil {
+				log.Printf("WARNING: Failed to get data source %s: %v", aws.ToString(summary.DataSourceId), err)
+				continue
+			}
+			if out.DataSource != nil {
+				sources = append(sources, *out.DataSource)
+			}
+		}
+	}
+
+	return sources, nil
+}
+
+// dataSourceBucket returns the bucket an S3 data source reads, or "" for
+// other source types.
+func dataSourceBucket(ds agenttypes.DataSource) string {
+	cfg := ds.DataSourceConfiguration
+	if cfg == nil || cfg.S3Configuration == nil {
+		return ""
+	}
+	_, bucket, _ := strings.Cut(aws.ToString(cfg.S3Configuration.BucketArn), ":::")
+	return bucket
+}
+
+// checkBedrockLogging returns a finding when model-invocation logging has
+// no CloudWatch or S3 destination.
+func (s *Scanner) checkBedrockLogging(ctx context.Context, target *Target) (*models.Finding, error) {
+	out, err := s.Client.Bedrock.GetModelInvocationLoggingConfiguration(ctx, &bedrock.GetModelInvocationLoggingConfigurationInput{})
+	if err != nil {
+		return nil, err
+	}
+	if cfg := out.LoggingConfig; cfg != nil && (cfg.CloudWatchConfig != nil || cfg.S3Config != nil) {
+		return nil, nil
+	}
+
+	f := bedrockFinding(target.Region, "invocation-logging", "Bedrock")
+	f.Risk = models.RiskMedium
+	f.Service = "Bedrock Invocation Logging Disabled"
+	f.Description = "Model invocations are not logged; prompt abuse and data leakage go unrecorded"
+	f.Evidence = "GetModelInvocationLoggingConfiguration: no CloudWatch or S3 destination"
+	return &f, nil
+}
@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	agenttypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func authorized(id string) *bedrock.GetFoundationModelAvailabilityOutput {
+	return &bedrock.GetFoundationModelAvailabilityOutput{
+		ModelId:                 aws.String(id),
+		AgreementAvailability:   &bedrocktypes.AgreementAvailability{Status: bedrocktypes.AgreementStatusAvailable},
+		AuthorizationStatus:     bedrocktypes.AuthorizationStatusAuthorized,
+		EntitlementAvailability: bedrocktypes.EntitlementAvailabilityAvailable,
+	}
+}
+
+func s3Source(id, bucket string) agenttypes.DataSource {
+	return agenttypes.DataSource{
+		DataSourceId: aws.String(id),
+		Name:         aws.String(id),
+		DataSourceConfiguration: &agenttypes.DataSourceConfiguration{
+			Type:            agenttypes.DataSourceTypeS3,
+			S3Configuration: &agenttypes.S3DataSourceConfiguration{BucketArn: aws.String("arn:aws:s3:::" + bucket)},
+		},
+	}
+}
+
+func TestScanBedrock(t *testing.T) {
+	br := &fake.Bedrock{
+		FoundationModels: []bedrocktypes.FoundationModelSummary{
+			{ModelId: aws.String("anthropic.claude-3-haiku")},
+			{ModelId: aws.String("meta.llama3-70b")},
+		},
+		Availability: map[string]*bedrock.GetFoundationModelAvailabilityOutput{
+			"anthropic.claude-3-haiku": authorized("anthropic.claude-3-haiku"),
+		},
+		CustomModels: []bedrocktypes.CustomModelSummary{
+			{ModelName: aws.String("support-ft"), BaseModelName: aws.String("Titan Text"), CustomizationType: bedrocktypes.CustomizationTypeFineTuning},
+		},
+		Provisioned: []bedrocktypes.ProvisionedModelSummary{
+			{ProvisionedModelName: aws.String("support-pt"), ModelUnits: aws.Int32(2), CommitmentDuration: bedrocktypes.CommitmentDurationSixMonths},
+		},
+		Guardrails: []bedrocktypes.GuardrailSummary{
+			{Id: aws.String("gr-1"), Name: aws.String("pii"), Version: aws.String("DRAFT")},
+		},
+	}
+	agents := &fake.BedrockAgent{
+		Agents: []agenttypes.AgentSummary{
+			{AgentId: aws.String("AG1"), AgentName: aws.String("helpdesk")},
+			{AgentId: aws.String("AG2"), AgentName: aws.String("triage"), GuardrailConfiguration: &agenttypes.GuardrailConfiguration{
+				GuardrailIdentifier: aws.String("gr-1"), GuardrailVersion: aws.String("1"),
+			}},
+		},
+		KnowledgeBases: []agenttypes.KnowledgeBaseSummary{
+			{KnowledgeBaseId: aws.String("KB1"), Name: aws.String("wiki")},
+			{KnowledgeBaseId: aws.String("KB2"), Name: aws.String("policies")},
+		},
+		DataSources: map[string][]agenttypes.DataSource{
+			"KB1": {s3Source("open", "open-docs")},
+			"KB2": {s3Source("private", "hr-docs")},
+		},
+	}
+	s3 := &fake.S3{Buckets: map[string]*fake.Bucket{
+		"open-docs": {Region: "us-east-1", Grants: []s3types.Grant{{Grantee: &s3types.Grantee{URI: aws.String("http://acs.amazonaws.com/groups/global/AllUsers")}}}},
+		"hr-docs":   {Region: "us-east-1", Encrypted: true},
+	}}
+
+	scn := newTestScanner(nil, nil, s3)
+	scn.Client.Bedrock = br
+	scn.Client.BedrockAgent = agents
+
+	findings, err := scn.ScanBedrock(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanBedrock: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]models.RiskLevel{
+		{"model-access", "Bedrock Model Access"}:                      models.RiskLow,
+		{"support-ft", "Bedrock Custom Model"}:                        models.RiskLow,
+		{"support-pt", "Bedrock Provisioned Throughput"}:              models.RiskLow,
+		{"gr-1", "Bedrock Guardrail"}:                                 models.RiskLow,
+		{"AG1", "Bedrock Agent Without Guardrail"}:                    models.RiskHigh,
+		{"AG2", "Bedrock Agent"}:                                      models.RiskLow,
+		{"KB1", "Bedrock Knowledge Base Public Source"}:               models.RiskHigh,
+		{"KB1", "Bedrock Knowledge Base Unencrypted Source"}:          models.RiskMedium,
+		{"KB2", "Bedrock Knowledge Base"}:                             models.RiskLow,
+		{"invocation-logging", "Bedrock Invocation Logging Disabled"}: models.RiskMedium,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k].Risk != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k].Risk, risk)
+		}
+	}
+
+	access := got[key{"model-access", "Bedrock Model Access"}]
+	if !strings.Contains(access.Evidence, "anthropic.claude-3-haiku") || strings.Contains(access.Evidence, "meta.llama3-70b") {
+		t.Errorf("model access evidence = %q, want only the authorized model", access.Evidence)
+	}
+}
+
+func TestScanBedrockLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		bedrock *fake.Bedrock
+		want    bool
+	}{
+		{
+			name:    "unused region",
+			bedrock: &fake.Bedrock{},
+		},
+		{
+			name: "only model access and guardrails",
+			bedrock: &fake.Bedrock{
+				FoundationModels: []bedrocktypes.FoundationModelSummary{{ModelId: aws.String("anthropic.claude-3-haiku")}},
+				Availability: map[string]*bedrock.GetFoundationModelAvailabilityOutput{
+					"anthropic.claude-3-haiku": authorized("anthropic.claude-3-haiku"),
+				},
+				Guardrails: []bedrocktypes.GuardrailSummary{{Id: aws.String("gr-1")}},
+			},
+		},
+		{
+			name: "logging to CloudWatch",
+			bedrock: &fake.Bedrock{
+				CustomModels: []bedrocktypes.CustomModelSummary{{ModelName: aws.String("support-ft")}},
+				Logging:      &bedrocktypes.LoggingConfig{CloudWatchConfig: &bedrocktypes.CloudWatchConfig{LogGroupName: aws.String("bedrock")}},
+			},
+		},
+		{
+			name: "logging without destination",
+			bedrock: &fake.Bedrock{
+				CustomModels: []bedrocktypes.CustomModelSummary{{ModelName: aws.String("support-ft")}},
+				Logging:      &bedrocktypes.LoggingConfig{TextDataDeliveryEnabled: aws.Bool(true)},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scn := newTestScanner(nil, nil, nil)
+			scn.Client.Bedrock = tt.bedrock
+
+			findings, err := scn.ScanBedrock(context.Background(), &Target{Region: "us-east-1"})
+			if err != nil {
+				t.Fatalf("ScanBedrock: %v", err)
+			}
+			flagged := false
+			for _, f := range findings {
+				if f.Service == "Bedrock Invocation Logging Disabled" {
+					flagged = true
+				}
+			}
+			if flagged != tt.want {
+				t.Errorf("logging flagged = %v, want %v", flagged, tt.want)
+			}
+		})
+	}
+}
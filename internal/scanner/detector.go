@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pterm/pterm"
+)
+
+// ResourceType names the kind of AWS resource a detector evaluates, using
+// CloudFormation type names.
+type ResourceType string
+
+const (
+	ResourceEC2Instance  ResourceType = "AWS::EC2::Instance"
+	ResourceS3Bucket     ResourceType = "AWS::S3::Bucket"
+	ResourceLoadBalancer ResourceType = "AWS::ElasticLoadBalancingV2::LoadBalancer"
+
+	ResourceSageMakerNotebook ResourceType = "AWS::SageMaker::NotebookInstance"
+	ResourceSageMakerEndpoint ResourceType = "AWS::SageMaker::Endpoint"
+	ResourceSageMakerDomain   ResourceType = "AWS::SageMaker::Domain"
+	ResourceSageMakerModel    ResourceType = "AWS::SageMaker::Model"
+
+	ResourceBedrockAgent         ResourceType = "AWS::Bedrock::Agent"
+	ResourceBedrockKnowledgeBase ResourceType = "AWS::Bedrock::KnowledgeBase"
+	ResourceBedrockGuardrail     ResourceType = "AWS::Bedrock::Guardrail"
+
+	ResourceLambdaFunction ResourceType = "AWS::Lambda::Function"
+	ResourceECSService     ResourceType = "AWS::ECS::Service"
+	ResourceECSTaskDef     ResourceType = "AWS::ECS::TaskDefinition"
+
+	ResourceECRRepository ResourceType = "AWS::ECR::Repository"
+
+	ResourceEKSCluster   ResourceType = "AWS::EKS::Cluster"
+	ResourceEKSNodegroup ResourceType = "AWS::EKS::Nodegroup"
+
+	ResourceLaunchTemplate   ResourceType = "AWS::EC2::LaunchTemplate"
+	ResourceAutoScalingGroup ResourceType = "AWS::AutoScaling::AutoScalingGroup"
+	ResourceSpotFleet        ResourceType = "AWS::EC2::SpotFleet"
+)
+
+// Scope tells the scanner how often a detector runs.
+type Scope string
+
+const (
+	// ScopeRegional detectors run once per scanned region.
+	ScopeRegional Scope = "regional"
+	// ScopeGlobal detectors run once per account, e.g. for S3.
+	ScopeGlobal Scope = "global"
+)
+
+// DetectorInfo describes a detector for orchestration and for
+// `ghostweights detectors list`.
+type DetectorInfo struct {
+	ID            string
+	Description   string
+	Permissions   []string
+	DefaultRisk   models.RiskLevel
+	ResourceTypes []ResourceType
+	Scope         Scope
+}
+
+// Target is the input handed to detectors for one scan pass. Instances is
+// only populated for regional passes and never contains excluded hosts;
+// those are in Excluded, so detectors that resolve resources by address can
+// skip them.
+type Target struct {
+	Region    string
+	Instances []types.Instance
+	Excluded  []types.Instance
+	Spinner   *pterm.SpinnerPrinter
+}
+
+// Detector is a single check run by the Scanner. Implementations register
+// themselves with Register, usually from an init function, so in-house
+// checks can be added with a blank import.
+type Detector interface {
+	Info() DetectorInfo
+	// Enabled reports whether the detector should run with the scanner's
+	// current settings, e.g. only when deep scanning is on.
+	Enabled(s *Scanner) bool
+	Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Detector{}
+)
+
+// Register adds a detector to the global registry. It panics if the ID is
+// empty or already taken.
+func Register(d Detector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	id := d.Info().ID
+	if id == "" {
+		panic("scanner: Register detector with empty ID")
+	}
+	if _, dup := registry[id]; dup {
+		panic("scanner: Register called twice for detector " + id)
+	}
+	registry[id] = d
+}
+
+// Detectors returns all registered detectors sorted by ID.
+func Detectors() []Detector {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]Detector, 0, len(registry))
+	for _, d := range registry {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Info().ID < list[j].Info().ID
+	})
+	return list
+}
+
+// PartialError is returned alongside the findings of a scan pass in which
+// some detectors failed. The findings of the others are still valid.
+type PartialError struct {
+	Err error
+}
+
+func (e *PartialError) Error() string {
+	return "some detectors failed: " + e.Err.Error()
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// runDetectors runs every enabled detector of the given scope against target.
+// A failing detector is logged and skipped so one broken check doesn't lose
+// the results of the others; the failures are returned as a *PartialError.
+func (s *Scanner) runDetectors(ctx context.Context, scope Scope, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+	var errs []error
+
+	for _, d := range s.Detectors {
+		info := d.Info()
+		if info.Scope != scope || !d.Enabled(s) {
+			continue
+		}
+
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Running detector %s...", info.ID))
+		found, err := d.Detect(ctx, s, target)
+		if err != nil {
+			log.Printf("WARNING: Detector %s failed: %v", info.ID, err)
+			errs = append(errs, fmt.Errorf("%s: %w", info.ID, err))
+			continue
+		}
+		findings = append(findings, found...)
+	}
+
+	if len(errs) > 0 {
+		return findings, &PartialError{Err: errors.Join(errs...)}
+	}
+	return findings, nil
+}
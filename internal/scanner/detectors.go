@@ -0,0 +1,553 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func init() {
+	Register(sgExposureDetector{})
+	Register(imdsDetector{})
+	Register(userDataDetector{})
+	Register(amiLineageDetector{})
+	Register(ssmDeepScanDetector{})
+	Register(s3BucketDetector{})
+	Register(elbExposureDetector{})
+	Register(sageMakerDetector{})
+	Register(bedrockDetector{})
+	Register(lambdaDetector{})
+	Register(ecsDetector{})
+	Register(eksDetector{})
+	Register(ecrDetector{})
+	Register(gpuInventoryDetector{})
+	Register(latentCapacityDetector{})
+}
+
+// sgExposureDetector flags AI ports from the port catalogue that security
+// groups open to the internet or to broad, foreign sources. With --probe,
+// each exposure is fingerprinted over HTTP and confirmed or downgraded, and
+// APIs that answer without credentials get findings of their own.
+type sgExposureDetector struct{}
+
+func (sgExposureDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "sg-exposure",
+		Description: "AI/ML ports open to the internet in security groups",
+		Permissions: []string{
+			"ec2:DescribeInstances", "ec2:DescribeSecurityGroups", "ec2:GetManagedPrefixListEntries",
+			"ec2:DescribeSubnets", "ec2:DescribeRouteTables", "ec2:DescribeNetworkAcls",
+		},
+		DefaultRisk:   models.RiskCritical,
+		ResourceTypes: []ResourceType{ResourceEC2Instance},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (sgExposureDetector) Enabled(s *Scanner) bool { return true }
+
+func (sgExposureDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+	seen := map[string]int{}
+	verdicts := map[string]reachability{}
+	// world marks findings from rules open to a whole address family; only
+	// those are probed, since narrower sources may not include us.
+	world := map[int]bool{}
+
+	ui.UpdateSpinner(target.Spinner, "Loading subnets, route tables and network ACLs...")
+	layout, err := s.loadNetwork(ctx)
+	if err != nil {
+		log.Printf("WARNING: Network reachability limited to public IP checks in %s: %v", target.Region, err)
+	}
+
+	for idx, instance := range target.Instances {
+		instanceID := aws.ToString(instance.InstanceId)
+		if instanceID == "" {
+			continue
+		}
+
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Scanning %s (%d/%d)...", instanceID, idx+1, len(target.Instances)))
+
+		for _, sg := range instance.SecurityGroups {
+			groupID := aws.ToString(sg.GroupId)
+			if groupID == "" {
+				continue
+			}
+
+			sgRules, err := s.getSecurityGroupRulesCached(ctx, groupID)
+			if err != nil {
+				log.Printf("WARNING: Failed to get rules for SG %s: %v", groupID, err)
+				continue
+			}
+
+			for _, rule := range sgRules {
+				if !isTCPOrAll(rule) {
+					continue
+				}
+				exp := s.ruleExposure(ctx, rule, s.sgOwners[groupID])
+				if exp.Cap == "" {
+					continue
+				}
+
+				for _, p := range s.Ports {
+					if !ruleCoversPort(rule, p.Port) {
+						continue
+					}
+
+					key := fmt.Sprintf("%s:%d:%s", instanceID, p.Port, p.Name)
+					var reach reachability
+					if exp.internet() {
+						reach = layout.reachable(instance, groupID, p.Port, exp.V4, exp.V6)
+					}
+
+					desc := p.Description
+					if desc == "" {
+						desc = fmt.Sprintf("Exposed %s port", p.Name)
+					}
+
+					f := models.Finding{
+						InstanceID:  instanceID,
+						Region:      target.Region,
+						PublicIP:    getPublicIP(instance),
+						PrivateIP:   getPrivateIP(instance),
+						NameTag:     getNameTag(instance.Tags),
+						Risk:        p.Risk.AtMost(exp.Cap),
+						Service:     p.Name,
+						Port:        p.Port,
+						Description: desc,
+						Evidence:    fmt.Sprintf("Port %d open to %s in SG %s", p.Port, exp.Source, groupID),
+					}
+					applyReachability(&f, reach)
+
+					// Several groups can open the same port; keep the one
+					// that gives the widest exposure.
+					if i, ok := seen[key]; ok {
+						old := findings[i]
+						if f.Risk.Rank() > old.Risk.Rank() || f.Risk == old.Risk && reach.rank() > verdicts[key].rank() {
+							findings[i] = f
+							verdicts[key] = reach
+							world[i] = exp.World
+						}
+						continue
+					}
+					seen[key] = len(findings)
+					verdicts[key] = reach
+					world[len(findings)] = exp.World
+					findings = append(findings, f)
+				}
+			}
+		}
+	}
+
+	if s.Prober != nil {
+		var targets []int
+		for i := range findings {
+			if world[i] {
+				targets = append(targets, i)
+			}
+		}
+		findings = append(findings, s.probeExposures(ctx, findings, targets, target.Spinner)...)
+	}
+
+	return findings, nil
+}
+
+// elbExposureDetector follows internet-facing load balancers to the AI
+// ports they forward to, which instance security groups don't show.
+type elbExposureDetector struct{}
+
+func (elbExposureDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "elb-exposure",
+		Description: "AI ports reachable through internet-facing ALBs and NLBs",
+		Permissions: []string{
+			"elasticloadbalancing:DescribeLoadBalancers", "elasticloadbalancing:DescribeListeners",
+			"elasticloadbalancing:DescribeRules", "elasticloadbalancing:DescribeTargetGroups",
+			"elasticloadbalancing:DescribeTargetHealth",
+			"ec2:DescribeSecurityGroups", "ec2:GetManagedPrefixListEntries",
+		},
+		DefaultRisk:   models.RiskCritical,
+		ResourceTypes: []ResourceType{ResourceLoadBalancer, ResourceEC2Instance},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (elbExposureDetector) Enabled(s *Scanner) bool { return true }
+
+func (elbExposureDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	if s.Client.ELB == nil {
+		return nil, nil
+	}
+	return s.ScanLoadBalancers(ctx, target)
+}
+
+// sageMakerDetector checks SageMaker notebooks, endpoints, Studio domains,
+// training jobs and models for risky settings.
+type sageMakerDetector struct{}
+
+func (sageMakerDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "sagemaker",
+		Description: "SageMaker notebooks, endpoints, Studio domains, training jobs and models with risky settings",
+		Permissions: []string{
+			"sagemaker:ListNotebookInstances", "sagemaker:DescribeNotebookInstance",
+			"sagemaker:ListEndpoints", "sagemaker:DescribeEndpoint", "sagemaker:DescribeEndpointConfig",
+			"sagemaker:ListDomains", "sagemaker:DescribeDomain",
+			"sagemaker:ListTrainingJobs", "sagemaker:DescribeTrainingJob",
+			"sagemaker:ListModels", "sagemaker:DescribeModel",
+			"s3:GetBucketLocation", "s3:GetBucketAcl", "s3:GetBucketPolicy", "s3:GetBucketEncryption",
+		},
+		DefaultRisk:   models.RiskHigh,
+		ResourceTypes: []ResourceType{ResourceSageMakerNotebook, ResourceSageMakerEndpoint, ResourceSageMakerDomain, ResourceSageMakerModel},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (sageMakerDetector) Enabled(s *Scanner) bool { return true }
+
+func (sageMakerDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	if s.Client.SageMaker == nil {
+		return nil, nil
+	}
+	return s.ScanSageMaker(ctx, target)
+}
+
+// bedrockDetector inventories Bedrock usage and flags agents without
+// guardrails, knowledge bases on exposed buckets and missing invocation
+// logging.
+type bedrockDetector struct{}
+
+func (bedrockDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "bedrock",
+		Description: "Bedrock model access, custom models, throughput, guardrails, agents, knowledge bases and invocation logging",
+		Permissions: []string{
+			"bedrock:ListFoundationModels", "bedrock:GetFoundationModelAvailability",
+			"bedrock:ListCustomModels", "bedrock:ListProvisionedModelThroughputs", "bedrock:ListGuardrails",
+			"bedrock:GetModelInvocationLoggingConfiguration",
+			"bedrock:ListAgents", "bedrock:ListKnowledgeBases", "bedrock:ListDataSources", "bedrock:GetDataSource",
+			"s3:GetBucketLocation", "s3:GetBucketAcl", "s3:GetBucketPolicy", "s3:GetBucketEncryption",
+		},
+		DefaultRisk:   models.RiskHigh,
+		ResourceTypes: []ResourceType{ResourceBedrockAgent, ResourceBedrockKnowledgeBase, ResourceBedrockGuardrail},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (bedrockDetector) Enabled(s *Scanner) bool { return true }
+
+func (bedrockDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	if s.Client.Bedrock == nil {
+		return nil, nil
+	}
+	return s.ScanBedrock(ctx, target)
+}
+
+// lambdaDetector flags functions that hold LLM keys, bundle AI SDKs or
+// large images, or expose model-backed function URLs without auth.
+type lambdaDetector struct{}
+
+func (lambdaDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "lambda",
+		Description: "Lambda functions with LLM API keys, AI SDKs, public function URLs or large images",
+		Permissions: []string{
+			"lambda:ListFunctions", "lambda:GetFunction", "lambda:ListFunctionUrlConfigs",
+			"ecr:DescribeImages",
+		},
+		DefaultRisk:   models.RiskCritical,
+		ResourceTypes: []ResourceType{ResourceLambdaFunction},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (lambdaDetector) Enabled(s *Scanner) bool { return true }
+
+func (lambdaDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	if s.Client.Lambda == nil {
+		return nil, nil
+	}
+	return s.ScanLambda(ctx, target)
+}
+
+// ecsDetector flags AI containers, GPU tasks, plaintext LLM keys and AI
+// ports on public-IP tasks in ECS and Fargate.
+type ecsDetector struct{}
+
+func (ecsDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "ecs",
+		Description: "ECS/Fargate task definitions with AI images, GPUs, LLM API keys or public AI ports",
+		Permissions: []string{
+			"ecs:ListClusters", "ecs:ListServices", "ecs:DescribeServices",
+			"ecs:ListTaskDefinitionFamilies", "ecs:DescribeTaskDefinition",
+			"ec2:DescribeSecurityGroups", "ec2:GetManagedPrefixListEntries",
+		},
+		DefaultRisk:   models.RiskCritical,
+		ResourceTypes: []ResourceType{ResourceECSService, ResourceECSTaskDef},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (ecsDetector) Enabled(s *Scanner) bool { return true }
+
+func (ecsDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	if s.Client.ECS == nil {
+		return nil, nil
+	}
+	return s.ScanECS(ctx, target)
+}
+
+// eksDetector flags public EKS API endpoints and GPU node groups, and with a
+// kubeconfig, AI Deployments and the Services exposing them.
+type eksDetector struct{}
+
+func (eksDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "eks",
+		Description: "EKS public API endpoints, GPU node groups and exposed AI workloads",
+		Permissions: []string{
+			"eks:ListClusters", "eks:DescribeCluster",
+			"eks:ListNodegroups", "eks:DescribeNodegroup",
+		},
+		DefaultRisk:   models.RiskHigh,
+		ResourceTypes: []ResourceType{ResourceEKSCluster, ResourceEKSNodegroup},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (eksDetector) Enabled(s *Scanner) bool { return true }
+
+func (eksDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	if s.Client.EKS == nil {
+		return nil, nil
+	}
+	return s.ScanEKS(ctx, target)
+}
+
+// ecrDetector flags AI images, weight-carrying layers and baked-in LLM keys
+// in ECR, and repositories other accounts can pull from.
+type ecrDetector struct{}
+
+func (ecrDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "ecr",
+		Description: "ECR images with AI runtimes, model weight layers or LLM API keys, and public or cross-account repositories",
+		Permissions: []string{
+			"ecr:DescribeRepositories", "ecr:GetRepositoryPolicy", "ecr:DescribeImages",
+			"ecr:BatchGetImage", "ecr:GetDownloadUrlForLayer",
+		},
+		DefaultRisk:   models.RiskCritical,
+		ResourceTypes: []ResourceType{ResourceECRRepository},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (ecrDetector) Enabled(s *Scanner) bool { return true }
+
+func (ecrDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	if s.Client.ECR == nil {
+		return nil, nil
+	}
+	return s.ScanECR(ctx, target)
+}
+
+// gpuInventoryDetector lists GPU and ML accelerator instances, including
+// stopped ones, with their on-demand cost. With --deep it also samples GPU
+// utilization and flags idle instances.
+type gpuInventoryDetector struct{}
+
+func (gpuInventoryDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "gpu-inventory",
+		Description: "GPU and ML accelerator instances, running or stopped, with cost and idle GPUs (--deep)",
+		Permissions: []string{
+			"ec2:DescribeInstances",
+			"ssm:DescribeInstanceInformation", "ssm:SendCommand", "ssm:GetCommandInvocation",
+		},
+		DefaultRisk:   models.RiskMedium,
+		ResourceTypes: []ResourceType{ResourceEC2Instance},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (gpuInventoryDetector) Enabled(s *Scanner) bool { return true }
+
+func (gpuInventoryDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	inventory, err := s.GPUInventory(ctx, target.Spinner)
+	if err != nil {
+		return nil, err
+	}
+	return gpuFindings(inventory), nil
+}
+
+// latentCapacityDetector flags launch templates, Auto Scaling groups and
+// Spot requests that can start GPU instances or bootstrap AI runtimes while
+// nothing is running.
+type latentCapacityDetector struct{}
+
+func (latentCapacityDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "latent-capacity",
+		Description: "Launch templates, Auto Scaling groups and Spot requests that launch GPU instances or install AI runtimes",
+		Permissions: []string{
+			"ec2:DescribeLaunchTemplates", "ec2:DescribeLaunchTemplateVersions",
+			"ec2:DescribeSpotInstanceRequests", "ec2:DescribeSpotFleetRequests",
+			"autoscaling:DescribeAutoScalingGroups", "autoscaling:DescribeLaunchConfigurations",
+		},
+		DefaultRisk:   models.RiskMedium,
+		ResourceTypes: []ResourceType{ResourceLaunchTemplate, ResourceAutoScalingGroup, ResourceSpotFleet},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (latentCapacityDetector) Enabled(s *Scanner) bool { return true }
+
+func (latentCapacityDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	return s.ScanLatentCapacity(ctx, target)
+}
+
+// imdsDetector flags instances that still accept IMDSv1 requests.
+type imdsDetector struct{}
+
+func (imdsDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:            "imdsv1",
+		Description:   "Instances allowing IMDSv1 (SSRF credential theft)",
+		Permissions:   []string{"ec2:DescribeInstances"},
+		DefaultRisk:   models.RiskHigh,
+		ResourceTypes: []ResourceType{ResourceEC2Instance},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (imdsDetector) Enabled(s *Scanner) bool { return true }
+
+func (imdsDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	for _, instance := range target.Instances {
+		if instance.MetadataOptions == nil || instance.MetadataOptions.HttpTokens != types.HttpTokensStateOptional {
+			continue
+		}
+
+		findings = append(findings, models.Finding{
+			InstanceID:  aws.ToString(instance.InstanceId),
+			Region:      target.Region,
+			PublicIP:    getPublicIP(instance),
+			PrivateIP:   getPrivateIP(instance),
+			NameTag:     getNameTag(instance.Tags),
+			Risk:        models.RiskHigh,
+			Service:     "IMDSv1 Enabled",
+			Description: "Instance allows IMDSv1 (SSRF vulnerable)",
+			Evidence:    "HttpTokens=optional allows unauthenticated metadata access",
+		})
+	}
+
+	return findings, nil
+}
+
+// userDataDetector flags AI runtime installs and LLM provider keys in
+// instance user data, without needing SSM.
+type userDataDetector struct{}
+
+func (userDataDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:            "user-data",
+		Description:   "AI runtime installs and LLM API keys in instance user data",
+		Permissions:   []string{"ec2:DescribeInstanceAttribute"},
+		DefaultRisk:   models.RiskCritical,
+		ResourceTypes: []ResourceType{ResourceEC2Instance},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (userDataDetector) Enabled(s *Scanner) bool { return true }
+
+func (userDataDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	return s.ScanUserData(ctx, target)
+}
+
+// amiLineageDetector flags instances launched from AI AMIs, or from private
+// AMIs built from them, without needing SSM.
+type amiLineageDetector struct{}
+
+func (amiLineageDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:            "ami-lineage",
+		Description:   "Instances launched from Deep Learning, NVIDIA NGC or marketplace LLM AMIs, or private AMIs built from them",
+		Permissions:   []string{"ec2:DescribeImages", "ec2:DescribeInstances"},
+		DefaultRisk:   models.RiskHigh,
+		ResourceTypes: []ResourceType{ResourceEC2Instance},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (amiLineageDetector) Enabled(s *Scanner) bool { return true }
+
+func (amiLineageDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	return s.ScanAMILineage(ctx, target)
+}
+
+// ssmDeepScanDetector inspects running instances over SSM. It only runs
+// with --deep.
+type ssmDeepScanDetector struct{}
+
+func (ssmDeepScanDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:            "ssm-deep-scan",
+		Description:   "AI processes, model files, GPUs and API keys found via SSM",
+		Permissions:   []string{"ssm:DescribeInstanceInformation", "ssm:SendCommand", "ssm:GetCommandInvocation"},
+		DefaultRisk:   models.RiskHigh,
+		ResourceTypes: []ResourceType{ResourceEC2Instance},
+		Scope:         ScopeRegional,
+	}
+}
+
+func (ssmDeepScanDetector) Enabled(s *Scanner) bool { return s.Deep }
+
+func (ssmDeepScanDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	var instanceIDs []string
+	for _, i := range target.Instances {
+		if i.InstanceId != nil {
+			instanceIDs = append(instanceIDs, *i.InstanceId)
+		}
+	}
+
+	findings, err := s.DeepScan(ctx, instanceIDs, target.Spinner)
+	if err != nil {
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("SSM Scan completed with errors: %v", err))
+		return nil, fmt.Errorf("SSM deep scan: %w", err)
+	}
+
+	return findings, nil
+}
+
+// s3BucketDetector looks for AI-related buckets that are public, unencrypted
+// or hold model files. It only runs with --s3.
+type s3BucketDetector struct{}
+
+func (s3BucketDetector) Info() DetectorInfo {
+	return DetectorInfo{
+		ID:          "s3-ai-buckets",
+		Description: "AI-related S3 buckets that are public, unencrypted or hold model files",
+		Permissions: []string{
+			"s3:ListAllMyBuckets", "s3:GetBucketLocation", "s3:GetBucketAcl",
+			"s3:GetBucketPolicy", "s3:GetBucketEncryption", "s3:ListBucket",
+		},
+		DefaultRisk:   models.RiskMedium,
+		ResourceTypes: []ResourceType{ResourceS3Bucket},
+		Scope:         ScopeGlobal,
+	}
+}
+
+func (s3BucketDetector) Enabled(s *Scanner) bool { return s.S3 }
+
+func (s3BucketDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	return s.ScanS3Buckets(ctx, target.Spinner)
+}
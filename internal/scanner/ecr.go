@@ -0,0 +1,494 @@
+package scanner
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+// ecrImagesPerRepo is how many of the most recently pushed images in each
+// repository are inspected; older images are usually superseded builds.
+const ecrImagesPerRepo = 5
+
+// ecrLargeLayerBytes is the compressed layer size above which a layer likely
+// carries model weights rather than code and libraries.
+const ecrLargeLayerBytes = 1 << 30
+
+// maxConfigBlobBytes bounds image config downloads. Configs are a few KB.
+const maxConfigBlobBytes = 4 << 20
+
+// ecrPullActions are the actions a principal needs to pull an image.
+var ecrPullActions = []string{"ecr:BatchGetImage", "ecr:GetDownloadUrlForLayer"}
+
+// modelDownload matches build steps that fetch model weights into a layer.
+var modelDownload = regexp.MustCompile(`(?i)huggingface-cli download|\bhf download|snapshot_download|ollama pull|from_pretrained`)
+
+// manifestMediaTypes are the single-platform manifest types the scan reads.
+// Multi-platform indexes are skipped; ECR lists their children as images.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+var blobClient = &http.Client{Timeout: 30 * time.Second}
+
+// imageManifest is the part of a Docker v2 or OCI image manifest the scan
+// reads.
+type imageManifest struct {
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Layers []struct {
+		Digest string `json:"digest"`
+		Size   int64  `json:"size"`
+	} `json:"layers"`
+}
+
+// imageConfig is the part of an image config blob the scan reads.
+type imageConfig struct {
+	Config struct {
+		Env    []string          `json:"Env"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+	History []struct {
+		CreatedBy  string `json:"created_by"`
+		EmptyLayer bool   `json:"empty_layer"`
+	} `json:"history"`
+}
+
+// ScanECR inspects the manifests and configs of the latest images in every
+// repository for AI base images, CUDA, weight-sized layers and plaintext LLM
+// keys, and flags repositories whose policy lets anyone or other accounts
+// pull.
+func (s *Scanner) ScanECR(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var repos []ecrtypes.Repository
+	paginator := ecr.NewDescribeRepositoriesPaginator(s.Client.ECR, &ecr.DescribeRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ECR repositories: %w", err)
+		}
+		repos = append(repos, page.Repositories...)
+	}
+
+	var findings []models.Finding
+	for idx, repo := range repos {
+		name := aws.ToString(repo.RepositoryName)
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking ECR repository %s (%d/%d)...", name, idx+1, len(repos)))
+
+		f, err := s.repositoryPolicyFinding(ctx, repo, target.Region)
+		if err != nil {
+			log.Printf("WARNING: Failed to check policy of ECR repository %s: %v", name, err)
+		} else if f != nil {
+			findings = append(findings, *f)
+		}
+
+		images, err := s.recentImages(ctx, repo)
+		if err != nil {
+			log.Printf("WARNING: Failed to list images in ECR repository %s: %v", name, err)
+			continue
+		}
+		manifests, err := s.imageManifests(ctx, repo, images)
+		if err != nil {
+			log.Printf("WARNING: Failed to get manifests in ECR repository %s: %v", name, err)
+			continue
+		}
+
+		for _, img := range images {
+			digest := aws.ToString(img.ImageDigest)
+			m, ok := manifests[digest]
+			if !ok {
+				continue
+			}
+			cfg, err := s.imageConfig(ctx, repo, m.Config.Digest)
+			if err != nil {
+				// Layer sizes alone still show weights.
+				log.Printf("WARNING: Failed to read config of %s@%s: %v", name, digest, err)
+			}
+			findings = append(findings, ecrImageFindings(name, img, m, cfg, target.Region)...)
+		}
+	}
+
+	return findings, nil
+}
+
+// recentImages returns the most recently pushed single-platform images in a
+// repository, newest first.
+func (s *Scanner) recentImages(ctx context.Context, repo ecrtypes.Repository) ([]ecrtypes.ImageDetail, error) {
+	var images []ecrtypes.ImageDetail
+	paginator := ecr.NewDescribeImagesPaginator(s.Client.ECR, &ecr.DescribeImagesInput{
+		RegistryId:     repo.RegistryId,
+		RepositoryName: repo.RepositoryName,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, img := range page.ImageDetails {
+			if mt := aws.ToString(img.ImageManifestMediaType); mt != "" && !slices.Contains(manifestMediaTypes, mt) {
+				continue
+			}
+			images = append(images, img)
+		}
+	}
+
+	slices.SortFunc(images, func(a, b ecrtypes.ImageDetail) int {
+		return cmp.Compare(aws.ToTime(b.ImagePushedAt).Unix(), aws.ToTime(a.ImagePushedAt).Unix())
+	})
+	return images[:min(len(images), ecrImagesPerRepo)], nil
+}
+
+// imageManifests fetches the manifests of images, keyed by digest.
+func (s *Scanner) imageManifests(ctx context.Context, repo ecrtypes.Repository, images []ecrtypes.ImageDetail) (map[string]imageManifest, error) {
+	if len(images) == 0 {
+		return nil, nil
+	}
+	ids := make([]ecrtypes.ImageIdentifier, 0, len(images))
+	for _, img := range images {
+		ids = append(ids, ecrtypes.ImageIdentifier{ImageDigest: img.ImageDigest})
+	}
+
+	out, err := s.Client.ECR.BatchGetImage(ctx, &ecr.BatchGetImageInput{
+		RegistryId:         repo.RegistryId,
+		RepositoryName:     repo.RepositoryName,
+		ImageIds:           ids,
+		AcceptedMediaTypes: manifestMediaTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := map[string]imageManifest{}
+	for _, img := range out.Images {
+		var m imageManifest
+		if img.ImageId == nil || json.Unmarshal([]byte(aws.ToString(img.ImageManifest)), &m) != nil {
+			continue
+		}
+		manifests[aws.ToString(img.ImageId.ImageDigest)] = m
+	}
+	return manifests, nil
+}
+
+// imageConfig downloads and decodes an image config blob.
+func (s *Scanner) imageConfig(ctx context.Context, repo ecrtypes.Repository, digest string) (imageConfig, error) {
+	var cfg imageConfig
+	if digest == "" {
+		return cfg, errors.New("manifest has no config")
+	}
+	out, err := s.Client.ECR.GetDownloadUrlForLayer(ctx, &ecr.GetDownloadUrlForLayerInput{
+		RegistryId:     repo.RegistryId,
+		RepositoryName: repo.RepositoryName,
+		LayerDigest:    aws.String(digest),
+	})
+	if err != nil {
+		return cfg, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, aws.ToString(out.DownloadUrl), nil)
+	if err != nil {
+		return cfg, err
+	}
+	resp, err := blobClient.Do(req)
+	if err != nil {
+		return cfg, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return cfg, fmt.Errorf("download config: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxConfigBlobBytes)).Decode(&cfg); err != nil {
+		return imageConfig{}, err
+	}
+	return cfg, nil
+}
+
+// ecrImageFindings reports AI base images and CUDA, layers that carry model
+// weights and plaintext keys in one image.
+func ecrImageFindings(repo string, img ecrtypes.ImageDetail, m imageManifest, cfg imageConfig, region string) []models.Finding {
+	var findings []models.Finding
+	id := repo + "@" + aws.ToString(img.ImageDigest)
+	name := repo
+	if len(img.ImageTags) > 0 {
+		name += ":" + img.ImageTags[0]
+	}
+	base := models.Finding{InstanceID: id, Region: region, PublicIP: "N/A", PrivateIP: "N/A", NameTag: name}
+
+	if signals := aiImageSignals(repo, cfg); len(signals) > 0 {
+		f := base
+		f.Risk = models.RiskLow
+		f.Service = "ECR AI Image"
+		f.Description = "Image is built for model serving or GPU inference"
+		f.Evidence = fmt.Sprintf("%s: %s", name, strings.Join(signals, ", "))
+		findings = append(findings, f)
+	}
+
+	if layers := weightLayers(m, cfg); len(layers) > 0 {
+		f := base
+		f.Risk = models.RiskMedium
+		f.Service = "ECR Model Weights Layer"
+		f.Description = fmt.Sprintf("Image has %d layers that likely carry model weights", len(layers))
+		f.Evidence = fmt.Sprintf("%s: %s", name, strings.Join(layers[:min(3, len(layers))], "; "))
+		findings = append(findings, f)
+	}
+
+	var keys []string
+	for _, entry := range cfg.Config.Env {
+		if name, value, _ := strings.Cut(entry, "="); isAPIKeyEntry(name, value) {
+			keys = append(keys, entry)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		f := base
+		f.Risk = models.RiskCritical
+		f.Service = "Exposed API Key"
+		f.Description = "API key baked into container image environment"
+		f.Evidence = fmt.Sprintf("%s: %s", name, maskAPIKey(key))
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// aiImageSignals lists what marks an image as AI-related: a catalogue image
+// as the repository or recorded base image, CUDA, or AI SDK installs.
+func aiImageSignals(repo string, cfg imageConfig) []string {
+	var signals []string
+	if img, ok := matchAIImage(repo); ok {
+		signals = append(signals, "mirror of "+img.Name)
+	}
+	if b := cfg.Config.Labels["org.opencontainers.image.base.name"]; b != "" {
+		if img, ok := matchAIImage(b); ok {
+			signals = append(signals, fmt.Sprintf("base image %s (%s)", b, img.Name))
+		}
+	}
+
+	cuda := ""
+	for _, entry := range cfg.Config.Env {
+		if v, ok := strings.CutPrefix(entry, "CUDA_VERSION="); ok {
+			cuda = "CUDA " + v
+		}
+	}
+	sdk := false
+	for _, h := range cfg.History {
+		step := strings.ToLower(h.CreatedBy)
+		if cuda == "" && strings.Contains(step, "cuda") {
+			cuda = "CUDA layer"
+		}
+		if strings.Contains(step, " install ") && mentionsAISDK(step) {
+			sdk = true
+		}
+	}
+	if cuda != "" {
+		signals = append(signals, cuda)
+	}
+	if sdk {
+		signals = append(signals, "AI SDK install step")
+	}
+	return signals
+}
+
+// weightLayers describes the layers that are large enough to hold weights or
+// whose build step copies model files or downloads a model. Non-empty
+// history entries pair with manifest layers in order.
+func weightLayers(m imageManifest, cfg imageConfig) []string {
+	var steps []string
+	for _, h := range cfg.History {
+		if !h.EmptyLayer {
+			steps = append(steps, h.CreatedBy)
+		}
+	}
+	if len(steps) != len(m.Layers) {
+		steps = nil
+	}
+
+	var layers []string
+	for i, l := range m.Layers {
+		step := ""
+		if steps != nil {
+			step = steps[i]
+		}
+		if l.Size < ecrLargeLayerBytes && !copiesModel(step) {
+			continue
+		}
+		desc := fmt.Sprintf("layer %s (%.1f GB)", shortDigest(l.Digest), float64(l.Size)/(1<<30))
+		if step != "" {
+			desc += ": " + truncate(buildStep(step), 80)
+		}
+		layers = append(layers, desc)
+	}
+	return layers
+}
+
+func copiesModel(step string) bool {
+	if modelDownload.MatchString(step) {
+		return true
+	}
+	for _, field := range strings.Fields(step) {
+		if isModelFile(strings.Trim(field, `"',[]`)) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildStep strips the shell wrapper Docker records in image history.
+func buildStep(createdBy string) string {
+	createdBy = strings.NewReplacer("/bin/sh -c ", "", "#(nop) ", "").Replace(createdBy)
+	return strings.Join(strings.Fields(createdBy), " ")
+}
+
+// shortDigest returns the first 12 hex digits of a sha256 digest.
+func shortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	return digest[:min(12, len(digest))]
+}
+
+// repositoryPolicyFinding reports a repository policy that lets any AWS
+// principal, or principals in other accounts, pull images. It returns nil
+// when the repository has no such policy.
+func (s *Scanner) repositoryPolicyFinding(ctx context.Context, repo ecrtypes.Repository, region string) (*models.Finding, error) {
+	out, err := s.Client.ECR.GetRepositoryPolicy(ctx, &ecr.GetRepositoryPolicyInput{
+		RegistryId:     repo.RegistryId,
+		RepositoryName: repo.RepositoryName,
+	})
+	var notFound *ecrtypes.RepositoryPolicyNotFoundException
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	public, accounts, err := pullPrincipals(aws.ToString(out.PolicyText), aws.ToString(repo.RegistryId))
+	if err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+
+	name := aws.ToString(repo.RepositoryName)
+	f := models.Finding{InstanceID: name, Region: region, PublicIP: "N/A", PrivateIP: "N/A", NameTag: name}
+	switch {
+	case public:
+		f.Risk = models.RiskHigh
+		f.Service = "ECR Public Pull Policy"
+		f.Description = "Any AWS account can pull images from the repository"
+		f.Evidence = fmt.Sprintf("Repository %s policy allows %s to Principal *", name, strings.Join(ecrPullActions, ", "))
+	case len(accounts) > 0:
+		f.Risk = models.RiskMedium
+		f.Service = "ECR Cross-Account Pull Policy"
+		f.Description = "Other AWS accounts can pull images from the repository"
+		f.Evidence = fmt.Sprintf("Repository %s policy allows pulls from %s", name, strings.Join(accounts, ", "))
+	default:
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// oneOrMany decodes IAM policy fields that hold either a value or a list.
+type oneOrMany[T any] []T
+
+func (o *oneOrMany[T]) UnmarshalJSON(b []byte) error {
+	var many []T
+	if err := json.Unmarshal(b, &many); err == nil {
+		*o = many
+		return nil
+	}
+	var one T
+	if err := json.Unmarshal(b, &one); err != nil {
+		return err
+	}
+	*o = oneOrMany[T]{one}
+	return nil
+}
+
+type policyStatement struct {
+	Effect    string
+	Principal json.RawMessage
+	Action    oneOrMany[string]
+	Condition json.RawMessage
+}
+
+// pullPrincipals reads a repository policy and reports whether it lets any
+// principal pull unconditionally, and which other accounts it lets pull. A
+// conditional wildcard, typically scoped to an organization, counts as
+// cross-account.
+func pullPrincipals(policy, owner string) (public bool, accounts []string, err error) {
+	var doc struct {
+		Statement oneOrMany[policyStatement]
+	}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return false, nil, err
+	}
+
+	for _, st := range doc.Statement {
+		if !strings.EqualFold(st.Effect, "Allow") || !allowsPull(st.Action) {
+			continue
+		}
+		for _, p := range awsPrincipals(st.Principal) {
+			switch account := principalAccount(p); {
+			case account == "*" && len(st.Condition) == 0:
+				public = true
+			case account == "*":
+				accounts = append(accounts, "* (conditional)")
+			case account != owner:
+				accounts = append(accounts, account)
+			}
+		}
+	}
+	slices.Sort(accounts)
+	return public, slices.Compact(accounts), nil
+}
+
+func allowsPull(actions []string) bool {
+	for _, pattern := range actions {
+		for _, action := range ecrPullActions {
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(action)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// awsPrincipals returns the AWS principals of a statement; "*" is returned
+// as is. Service principals are ignored.
+func awsPrincipals(raw json.RawMessage) []string {
+	var wildcard string
+	if json.Unmarshal(raw, &wildcard) == nil {
+		return []string{wildcard}
+	}
+	var principals struct {
+		AWS oneOrMany[string]
+	}
+	if json.Unmarshal(raw, &principals) != nil {
+		return nil
+	}
+	return principals.AWS
+}
+
+// principalAccount returns the account of an AWS principal given as an
+// account ID or an IAM ARN.
+func principalAccount(p string) string {
+	if parts := strings.Split(p, ":"); len(parts) > 4 && parts[0] == "arn" {
+		return parts[4]
+	}
+	return p
+}
@@ -0,0 +1,153 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+func TestScanECR(t *testing.T) {
+	blobs := map[string]string{
+		"sha256:cfg-llm": `{
+			"config": {
+				"Env": ["PATH=/usr/bin", "CUDA_VERSION=12.4.1", "OPENAI_BASE_URL=https://api.openai.com/v1", "OPENAI_API_KEY=sk-abcdefghijklmnop"],
+				"Labels": {"org.opencontainers.image.base.name": "docker.io/vllm/vllm-openai:v0.6.3"}
+			},
+			"history": [
+				{"created_by": "/bin/sh -c #(nop) ADD file:abc in /"},
+				{"created_by": "ENV CUDA_VERSION=12.4.1", "empty_layer": true},
+				{"created_by": "RUN /bin/sh -c huggingface-cli download meta-llama/Llama-3.1-8B --local-dir /models"},
+				{"created_by": "COPY app.py /app/"}
+			]
+		}`,
+		"sha256:cfg-web": `{"config": {"Env": ["PATH=/usr/bin"]}, "history": [{"created_by": "COPY . /app"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blob, ok := blobs[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(blob))
+	}))
+	defer srv.Close()
+
+	pushed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry := &fake.ECR{
+		Repositories: []ecrtypes.Repository{
+			{RepositoryName: aws.String("llm-server"), RegistryId: aws.String("111111111111")},
+			{RepositoryName: aws.String("web"), RegistryId: aws.String("111111111111")},
+			{RepositoryName: aws.String("shared"), RegistryId: aws.String("111111111111")},
+		},
+		Policies: map[string]string{
+			"llm-server": `{"Statement": {"Effect": "Allow", "Principal": "*", "Action": ["ecr:BatchGetImage", "ecr:GetDownloadUrlForLayer"]}}`,
+			"shared": `{"Statement": [
+				{"Effect": "Allow", "Principal": {"AWS": ["arn:aws:iam::222222222222:root", "111111111111"]}, "Action": "ecr:*"},
+				{"Effect": "Allow", "Principal": {"Service": "lambda.amazonaws.com"}, "Action": "ecr:BatchGetImage"}
+			]}`,
+			"web": `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::333333333333:root"}, "Action": "ecr:PutImage"}]}`,
+		},
+		Images: map[string]ecrtypes.ImageDetail{
+			"llm-server@sha256:llm": {ImageTags: []string{"v2"}, ImagePushedAt: aws.Time(pushed)},
+			"web@sha256:web":        {ImageTags: []string{"latest"}, ImagePushedAt: aws.Time(pushed)},
+		},
+		Manifests: map[string]string{
+			"llm-server@sha256:llm": `{"config": {"digest": "sha256:cfg-llm"}, "layers": [
+				{"digest": "sha256:base", "size": 30000000},
+				{"digest": "sha256:weights", "size": 16000000000},
+				{"digest": "sha256:app", "size": 4000}
+			]}`,
+			"web@sha256:web": `{"config": {"digest": "sha256:cfg-web"}, "layers": [{"digest": "sha256:app", "size": 4000}]}`,
+		},
+		BlobURL: srv.URL,
+	}
+
+	scn := newTestScanner(nil, nil, nil)
+	scn.Client.ECR = registry
+
+	findings, err := scn.ScanECR(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanECR: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]models.RiskLevel{
+		{"llm-server", "ECR Public Pull Policy"}:             models.RiskHigh,
+		{"shared", "ECR Cross-Account Pull Policy"}:          models.RiskMedium,
+		{"llm-server@sha256:llm", "ECR AI Image"}:            models.RiskLow,
+		{"llm-server@sha256:llm", "ECR Model Weights Layer"}: models.RiskMedium,
+		{"llm-server@sha256:llm", "Exposed API Key"}:         models.RiskCritical,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k].Risk != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k].Risk, risk)
+		}
+	}
+
+	if ev := got[key{"llm-server@sha256:llm", "ECR AI Image"}].Evidence; !strings.Contains(ev, "vLLM") || !strings.Contains(ev, "CUDA 12.4.1") {
+		t.Errorf("AI image evidence = %q, want base image and CUDA", ev)
+	}
+	if ev := got[key{"llm-server@sha256:llm", "ECR Model Weights Layer"}].Evidence; !strings.Contains(ev, "layer weights (14.9 GB): RUN huggingface-cli download") {
+		t.Errorf("weights evidence = %q, want layer and build step", ev)
+	}
+	if ev := got[key{"llm-server@sha256:llm", "Exposed API Key"}].Evidence; ev != "llm-server:v2: OPENAI_API_KEY=sk-a***mnop" {
+		t.Errorf("key evidence = %q, want masked key", ev)
+	}
+	if ev := got[key{"shared", "ECR Cross-Account Pull Policy"}].Evidence; !strings.HasSuffix(ev, "pulls from 222222222222") {
+		t.Errorf("cross-account evidence = %q, want only the foreign account", ev)
+	}
+}
+
+func TestPullPrincipals(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		public   bool
+		accounts []string
+	}{
+		{
+			name:   "wildcard",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "ecr:BatchGet*"}]}`,
+			public: true,
+		},
+		{
+			name:     "organization",
+			policy:   `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "*", "Condition": {"StringEquals": {"aws:PrincipalOrgID": "o-abc"}}}]}`,
+			accounts: []string{"* (conditional)"},
+		},
+		{
+			name:   "deny",
+			policy: `{"Statement": [{"Effect": "Deny", "Principal": "*", "Action": "ecr:*"}]}`,
+		},
+		{
+			name:   "same account",
+			policy: `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::111111111111:role/ci"}, "Action": "ecr:GetDownloadUrlForLayer"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		public, accounts, err := pullPrincipals(tt.policy, "111111111111")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if public != tt.public || strings.Join(accounts, ",") != strings.Join(tt.accounts, ",") {
+			t.Errorf("%s: got public=%v accounts=%v, want %v %v", tt.name, public, accounts, tt.public, tt.accounts)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// aiImage is a container image known to serve or run models.
+type aiImage struct {
+	// Repo is matched against the end of the image path, so mirrors such as
+	// ECR pull-through caches of the same repository also match.
+	Repo string
+	Name string
+}
+
+// aiImages is the catalogue of well-known AI serving and notebook images.
+var aiImages = []aiImage{
+	{"ollama/ollama", "Ollama"},
+	{"vllm/vllm-openai", "vLLM"},
+	{"huggingface/text-generation-inference", "Text Generation Inference"},
+	{"huggingface/text-embeddings-inference", "Text Embeddings Inference"},
+	{"nvidia/tritonserver", "Triton Inference Server"},
+	{"localai/localai", "LocalAI"},
+	{"go-skynet/local-ai", "LocalAI"},
+	{"open-webui/open-webui", "Open WebUI"},
+	{"ggerganov/llama.cpp", "llama.cpp"},
+	{"ggml-org/llama.cpp", "llama.cpp"},
+	{"rayproject/ray", "Ray"},
+	{"rayproject/ray-ml", "Ray"},
+	{"mlflow/mlflow", "MLflow"},
+	{"pytorch/torchserve", "TorchServe"},
+	{"tensorflow/serving", "TensorFlow Serving"},
+	{"jupyter/base-notebook", "Jupyter"},
+	{"jupyter/minimal-notebook", "Jupyter"},
+	{"jupyter/scipy-notebook", "Jupyter"},
+	{"jupyter/datascience-notebook", "Jupyter"},
+	{"jupyter/pytorch-notebook", "Jupyter"},
+	{"jupyter/tensorflow-notebook", "Jupyter"},
+}
+
+// matchAIImage returns the catalogue entry for an image reference such as
+// "ghcr.io/huggingface/text-generation-inference:2.0".
+func matchAIImage(image string) (aiImage, bool) {
+	name := strings.ToLower(imageName(image))
+	for _, img := range aiImages {
+		if name == img.Repo || strings.HasSuffix(name, "/"+img.Repo) {
+			return img, true
+		}
+	}
+	return aiImage{}, false
+}
+
+// imageName strips the tag or digest from an image reference, keeping the
+// registry host, which may carry a port.
+func imageName(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	slash := strings.LastIndex(image, "/")
+	if i := strings.LastIndex(image, ":"); i > slash {
+		image = image[:i]
+	}
+	return image
+}
+
+// ScanECS checks the task definitions behind every ECS service, and the
+// latest revision of every other active task definition family, for AI
+// images, GPU requirements and plaintext LLM keys. Services that give tasks
+// a public IP are checked against the port catalogue and their security
+// groups.
+func (s *Scanner) ScanECS(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var clusters []string
+	paginator := ecs.NewListClustersPaginator(s.Client.ECS, &ecs.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ECS clusters: %w", err)
+		}
+		clusters = append(clusters, page.ClusterArns...)
+	}
+
+	var findings []models.Finding
+	scanned := map[string]bool{}
+
+	for idx, cluster := range clusters {
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking ECS cluster %s (%d/%d)...", resourceName(cluster), idx+1, len(clusters)))
+
+		services, err := s.clusterServices(ctx, cluster)
+		if err != nil {
+			log.Printf("WARNING: Failed to list services in ECS cluster %s: %v", resourceName(cluster), err)
+			continue
+		}
+
+		for _, svc := range services {
+			td, err := s.taskDefinition(ctx, aws.ToString(svc.TaskDefinition))
+			if err != nil {
+				log.Printf("WARNING: Failed to describe task definition %s: %v", aws.ToString(svc.TaskDefinition), err)
+				continue
+			}
+			key := taskDefinitionKey(td)
+			if !scanned[key] {
+				scanned[key] = true
+				findings = append(findings, taskDefinitionFindings(td, target.Region)...)
+			}
+			findings = append(findings, s.publicTaskFindings(ctx, cluster, svc, td, target.Region)...)
+		}
+	}
+
+	// Task definitions run as one-off or scheduled tasks have no service.
+	families := ecs.NewListTaskDefinitionFamiliesPaginator(s.Client.ECS, &ecs.ListTaskDefinitionFamiliesInput{
+		Status: ecstypes.TaskDefinitionFamilyStatusActive,
+	})
+	for families.HasMorePages() {
+		page, err := families.NextPage(ctx)
+		if err != nil {
+			log.Printf("WARNING: Failed to list task definition families in %s: %v", target.Region, err)
+			break
+		}
+		for _, family := range page.Families {
+			td, err := s.taskDefinition(ctx, family)
+			if err != nil {
+				log.Printf("WARNING: Failed to describe task definition %s: %v", family, err)
+				continue
+			}
+			if key := taskDefinitionKey(td); !scanned[key] {
+				scanned[key] = true
+				findings = append(findings, taskDefinitionFindings(td, target.Region)...)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) clusterServices(ctx context.Context, cluster string) ([]ecstypes.Service, error) {
+	var arns []string
+	paginator := ecs.NewListServicesPaginator(s.Client.ECS, &ecs.ListServicesInput{Cluster: aws.String(cluster)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		arns = append(arns, page.ServiceArns...)
+	}
+
+	var services []ecstypes.Service
+	// DescribeServices accepts at most 10 services per call.
+	for batch := range slices.Chunk(arns, 10) {
+		out, err := s.Client.ECS.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: batch,
+		})
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, out.Services...)
+	}
+	return services, nil
+}
+
+func (s *Scanner) taskDefinition(ctx context.Context, ref string) (ecstypes.TaskDefinition, error) {
+	out, err := s.Client.ECS.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(ref)})
+	if err != nil {
+		return ecstypes.TaskDefinition{}, err
+	}
+	if out.TaskDefinition == nil {
+		return ecstypes.TaskDefinition{}, fmt.Errorf("task definition %s not returned", ref)
+	}
+	return *out.TaskDefinition, nil
+}
+
+// taskDefinitionKey is "family:revision", the resource ID used in findings.
+func taskDefinitionKey(td ecstypes.TaskDefinition) string {
+	return fmt.Sprintf("%s:%d", aws.ToString(td.Family), td.Revision)
+}
+
+// taskDefinitionFindings reports AI images, GPU or accelerator requirements
+// and plaintext keys in one task definition.
+func taskDefinitionFindings(td ecstypes.TaskDefinition, region string) []models.Finding {
+	var findings []models.Finding
+	id := taskDefinitionKey(td)
+	base := models.Finding{InstanceID: id, Region: region, PublicIP: "N/A", PrivateIP: "N/A", NameTag: aws.ToString(td.Family)}
+
+	for _, c := range td.ContainerDefinitions {
+		container := aws.ToString(c.Name)
+		image := aws.ToString(c.Image)
+
+		if img, ok := matchAIImage(image); ok {
+			f := base
+			f.Risk = models.RiskMedium
+			f.Service = "ECS AI Container"
+			f.Description = fmt.Sprintf("%s container in task definition", img.Name)
+			f.Evidence = fmt.Sprintf("Task definition %s container %s runs %s", id, container, image)
+			findings = append(findings, f)
+		}
+
+		for _, r := range c.ResourceRequirements {
+			if r.Type != ecstypes.ResourceTypeGpu {
+				continue
+			}
+			f := base
+			f.Risk = models.RiskMedium
+			f.Service = "ECS GPU Task"
+			f.Description = fmt.Sprintf("Container requests %s GPUs", aws.ToString(r.Value))
+			f.Evidence = fmt.Sprintf("Task definition %s container %s: resourceRequirements GPU=%s", id, container, aws.ToString(r.Value))
+			findings = append(findings, f)
+		}
+
+		var keys []string
+		for _, kv := range c.Environment {
+			if name, value := aws.ToString(kv.Name), aws.ToString(kv.Value); isAPIKeyEntry(name, value) {
+				keys = append(keys, name+"="+value)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			f := base
+			f.Risk = models.RiskCritical
+			f.Service = "Exposed API Key"
+			f.Description = "API key found in ECS container environment"
+			f.Evidence = fmt.Sprintf("Container %s: %s", container, maskAPIKey(key))
+			findings = append(findings, f)
+		}
+	}
+
+	for _, acc := range td.InferenceAccelerators {
+		f := base
+		f.Risk = models.RiskMedium
+		f.Service = "ECS GPU Task"
+		f.Description = "Task definition requests an inference accelerator"
+		f.Evidence = fmt.Sprintf("Task definition %s: inference accelerator %s (%s)", id, aws.ToString(acc.DeviceName), aws.ToString(acc.DeviceType))
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// publicTaskFindings reports the AI ports of an awsvpc service whose tasks
+// get a public IP. A port counts when it is in the port catalogue or served
+// by a known AI image, and its risk is capped by how widely the service's
+// security groups open it.
+func (s *Scanner) publicTaskFindings(ctx context.Context, cluster string, svc ecstypes.Service, td ecstypes.TaskDefinition, region string) []models.Finding {
+	nc := svc.NetworkConfiguration
+	if nc == nil || nc.AwsvpcConfiguration == nil || nc.AwsvpcConfiguration.AssignPublicIp != ecstypes.AssignPublicIpEnabled {
+		return nil
+	}
+	groups := nc.AwsvpcConfiguration.SecurityGroups
+
+	ports := map[int32]AIPort{}
+	for _, p := range s.Ports {
+		ports[p.Port] = p
+	}
+
+	var findings []models.Finding
+	name := aws.ToString(svc.ServiceName)
+
+	for _, c := range td.ContainerDefinitions {
+		img, isAI := matchAIImage(aws.ToString(c.Image))
+
+		for _, pm := range c.PortMappings {
+			port := aws.ToInt32(pm.ContainerPort)
+			p, ok := ports[port]
+			if !ok {
+				if !isAI {
+					continue
+				}
+				p = AIPort{Port: port, Name: img.Name, Risk: models.RiskHigh}
+			}
+
+			exp := s.widestGroupExposure(ctx, groups, port)
+			if exp.Cap == "" {
+				continue
+			}
+
+			findings = append(findings, models.Finding{
+				InstanceID:  name,
+				Region:      region,
+				PublicIP:    "N/A",
+				PrivateIP:   "N/A",
+				NameTag:     name,
+				Risk:        p.Risk.AtMost(exp.Cap),
+				Service:     p.Name + " on ECS",
+				Port:        port,
+				Description: fmt.Sprintf("%s on ECS tasks with a public IP", p.Name),
+				Evidence: fmt.Sprintf("Service %s in cluster %s (%s): AssignPublicIp=ENABLED, container %s port %d open to %s",
+					name, resourceName(cluster), taskDefinitionKey(td), aws.ToString(c.Name), port, exp.Source),
+			})
+		}
+	}
+
+	return findings
+}
+
+// resourceName returns the part of an ARN after the last slash.
+func resourceName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
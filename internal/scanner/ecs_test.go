@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+func testService(name, taskDef string, public ecstypes.AssignPublicIp, groups ...string) ecstypes.Service {
+	return ecstypes.Service{
+		ServiceName:    aws.String(name),
+		TaskDefinition: aws.String("arn:aws:ecs:us-east-1:111111111111:task-definition/" + taskDef),
+		NetworkConfiguration: &ecstypes.NetworkConfiguration{AwsvpcConfiguration: &ecstypes.AwsVpcConfiguration{
+			AssignPublicIp: public,
+			SecurityGroups: groups,
+		}},
+	}
+}
+
+func TestScanECS(t *testing.T) {
+	ec2 := &fake.EC2{SecurityGroups: []types.SecurityGroup{
+		{GroupId: aws.String("sg-open"), IpPermissions: []types.IpPermission{
+			tcpRule(11434, 11434, "0.0.0.0/0"),
+			tcpRule(80, 80, "0.0.0.0/0"),
+		}},
+		{GroupId: aws.String("sg-closed")},
+	}}
+	svc := &fake.ECS{
+		Services: map[string][]ecstypes.Service{
+			"arn:aws:ecs:us-east-1:111111111111:cluster/ml": {
+				testService("ollama", "ollama:3", ecstypes.AssignPublicIpEnabled, "sg-open"),
+				testService("tgi", "tgi:1", ecstypes.AssignPublicIpEnabled, "sg-open"),
+				testService("tgi-private", "tgi:1", ecstypes.AssignPublicIpEnabled, "sg-closed"),
+			},
+		},
+		TaskDefinitions: map[string]ecstypes.TaskDefinition{
+			"ollama:3": {
+				Family:   aws.String("ollama"),
+				Revision: 3,
+				ContainerDefinitions: []ecstypes.ContainerDefinition{{
+					Name:                 aws.String("ollama"),
+					Image:                aws.String("ollama/ollama:0.3.12"),
+					PortMappings:         []ecstypes.PortMapping{{ContainerPort: aws.Int32(11434)}},
+					ResourceRequirements: []ecstypes.ResourceRequirement{{Type: ecstypes.ResourceTypeGpu, Value: aws.String("1")}},
+				}},
+			},
+			"tgi:1": {
+				Family:   aws.String("tgi"),
+				Revision: 1,
+				ContainerDefinitions: []ecstypes.ContainerDefinition{{
+					Name:         aws.String("tgi"),
+					Image:        aws.String("111111111111.dkr.ecr.us-east-1.amazonaws.com/ghcr/huggingface/text-generation-inference@sha256:abc"),
+					PortMappings: []ecstypes.PortMapping{{ContainerPort: aws.Int32(80)}},
+				}},
+			},
+			"batch:7": {
+				Family:   aws.String("batch"),
+				Revision: 7,
+				ContainerDefinitions: []ecstypes.ContainerDefinition{{
+					Name:  aws.String("worker"),
+					Image: aws.String("python:3.12"),
+					Environment: []ecstypes.KeyValuePair{
+						{Name: aws.String("ANTHROPIC_API_KEY"), Value: aws.String("sk-ant-abcdefghijkl")},
+						{Name: aws.String("ANTHROPIC_MODEL"), Value: aws.String("claude-3-5-sonnet-latest")},
+						{Name: aws.String("OPENAI_BASE_URL"), Value: aws.String("https://api.openai.com/v1")},
+						{Name: aws.String("QUEUE"), Value: aws.String("jobs")},
+					},
+				}},
+			},
+			"batch:6": {Family: aws.String("batch"), Revision: 6},
+		},
+	}
+
+	scn := newTestScanner(ec2, nil, nil)
+	scn.Client.ECS = svc
+
+	findings, err := scn.ScanECS(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanECS: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]models.RiskLevel{
+		{"ollama:3", "ECS AI Container"}:            models.RiskMedium,
+		{"ollama:3", "ECS GPU Task"}:                models.RiskMedium,
+		{"ollama", "Ollama API on ECS"}:             models.RiskCritical,
+		{"tgi:1", "ECS AI Container"}:               models.RiskMedium,
+		{"tgi", "Text Generation Inference on ECS"}: models.RiskHigh,
+		{"batch:7", "Exposed API Key"}:              models.RiskCritical,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k].Risk != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k].Risk, risk)
+		}
+	}
+
+	if ev := got[key{"batch:7", "Exposed API Key"}].Evidence; ev != "Container worker: ANTHROPIC_API_KEY=sk-a***ijkl" {
+		t.Errorf("key evidence = %q, want masked key", ev)
+	}
+	if ev := got[key{"ollama", "Ollama API on ECS"}].Evidence; !strings.Contains(ev, "AssignPublicIp=ENABLED") || !strings.Contains(ev, "0.0.0.0/0") {
+		t.Errorf("public task evidence = %q", ev)
+	}
+}
+
+func TestMatchAIImage(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"ollama/ollama", "Ollama"},
+		{"docker.io/vllm/vllm-openai:v0.6.3", "vLLM"},
+		{"ghcr.io/huggingface/text-generation-inference:2.4", "Text Generation Inference"},
+		{"registry.internal:5000/mirror/nvidia/tritonserver:24.08-py3", "Triton Inference Server"},
+		{"quay.io/jupyter/pytorch-notebook@sha256:abc", "Jupyter"},
+		{"myorg/ollama-proxy:latest", ""},
+		{"python:3.12", ""},
+	}
+	for _, tt := range tests {
+		img, _ := matchAIImage(tt.image)
+		if img.Name != tt.want {
+			t.Errorf("matchAIImage(%q) = %q, want %q", tt.image, img.Name, tt.want)
+		}
+	}
+}
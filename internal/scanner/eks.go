@@ -0,0 +1,179 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// gpuAMITypes are node group AMI types that only make sense on GPU or
+// Neuron hardware. They catch node groups whose instance types live in a
+// launch template.
+var gpuAMITypes = []ekstypes.AMITypes{
+	ekstypes.AMITypesAl2X8664Gpu,
+	ekstypes.AMITypesAl2023X8664Nvidia,
+	ekstypes.AMITypesAl2023Arm64Nvidia,
+	ekstypes.AMITypesAl2023X8664Neuron,
+	ekstypes.AMITypesBottlerocketX8664Nvidia,
+	ekstypes.AMITypesBottlerocketArm64Nvidia,
+	ekstypes.AMITypesBottlerocketX8664NvidiaFips,
+	ekstypes.AMITypesBottlerocketArm64NvidiaFips,
+}
+
+// ScanEKS flags clusters whose API endpoint is public and managed node
+// groups that run GPU instances. When s.Kubeconfig has a context for a
+// cluster, its Deployments and Services are checked too.
+func (s *Scanner) ScanEKS(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var names []string
+	paginator := eks.NewListClustersPaginator(s.Client.EKS, &eks.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list EKS clusters: %w", err)
+		}
+		names = append(names, page.Clusters...)
+	}
+
+	var findings []models.Finding
+	for idx, name := range names {
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking EKS cluster %s (%d/%d)...", name, idx+1, len(names)))
+
+		out, err := s.Client.EKS.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String(name)})
+		if err != nil {
+			log.Printf("WARNING: Failed to describe EKS cluster %s: %v", name, err)
+			continue
+		}
+		cluster := *out.Cluster
+
+		if f, ok := publicEndpointFinding(cluster, target.Region); ok {
+			findings = append(findings, f)
+		}
+
+		groups, err := s.gpuNodegroups(ctx, name, target.Region)
+		if err != nil {
+			log.Printf("WARNING: Failed to list node groups of EKS cluster %s: %v", name, err)
+		}
+		findings = append(findings, groups...)
+
+		if s.Kubeconfig == "" {
+			continue
+		}
+		cs, err := kubeClientForEndpoint(s.Kubeconfig, aws.ToString(cluster.Endpoint))
+		if err != nil {
+			log.Printf("WARNING: Failed to load kubeconfig for EKS cluster %s: %v", name, err)
+			continue
+		}
+		if cs == nil {
+			continue
+		}
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking workloads in EKS cluster %s...", name))
+		workloads, err := s.scanWorkloads(ctx, cs, name, target.Region)
+		if err != nil {
+			log.Printf("WARNING: Failed to list workloads in EKS cluster %s: %v", name, err)
+			continue
+		}
+		findings = append(findings, workloads...)
+	}
+
+	return findings, nil
+}
+
+// publicEndpointFinding reports a cluster API endpoint reachable from the
+// internet. The API still requires credentials, so the risk tops out at
+// HIGH and shrinks with the allowed source ranges.
+func publicEndpointFinding(cluster ekstypes.Cluster, region string) (models.Finding, bool) {
+	vpc := cluster.ResourcesVpcConfig
+	if vpc == nil || !vpc.EndpointPublicAccess {
+		return models.Finding{}, false
+	}
+	cidrs := vpc.PublicAccessCidrs
+	if len(cidrs) == 0 {
+		cidrs = []string{"0.0.0.0/0"}
+	}
+	exp := mixedCIDRExposure(cidrs)
+	if exp.Cap == "" {
+		return models.Finding{}, false
+	}
+
+	name := aws.ToString(cluster.Name)
+	return models.Finding{
+		InstanceID:  name,
+		Region:      region,
+		PublicIP:    "N/A",
+		PrivateIP:   "N/A",
+		NameTag:     name,
+		Risk:        models.RiskHigh.AtMost(exp.Cap),
+		Service:     "EKS Public API Endpoint",
+		Description: "Kubernetes API server is reachable from the internet",
+		Evidence: fmt.Sprintf("Cluster %s endpoint %s public access from %s (private access: %t)",
+			name, aws.ToString(cluster.Endpoint), strings.Join(cidrs, ", "), vpc.EndpointPrivateAccess),
+	}, true
+}
+
+func (s *Scanner) gpuNodegroups(ctx context.Context, cluster, region string) ([]models.Finding, error) {
+	var names []string
+	paginator := eks.NewListNodegroupsPaginator(s.Client.EKS, &eks.ListNodegroupsInput{ClusterName: aws.String(cluster)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, page.Nodegroups...)
+	}
+
+	var findings []models.Finding
+	for _, name := range names {
+		out, err := s.Client.EKS.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String(cluster),
+			NodegroupName: aws.String(name),
+		})
+		if err != nil {
+			log.Printf("WARNING: Failed to describe node group %s: %v", name, err)
+			continue
+		}
+		ng := out.Nodegroup
+
+		var gpuTypes []string
+		for _, t := range ng.InstanceTypes {
+			if isGPUInstanceType(t) {
+				gpuTypes = append(gpuTypes, t)
+			}
+		}
+		if len(gpuTypes) == 0 && !slices.Contains(gpuAMITypes, ng.AmiType) {
+			continue
+		}
+
+		evidence := fmt.Sprintf("Node group %s in cluster %s: AMI %s", name, cluster, ng.AmiType)
+		if len(gpuTypes) > 0 {
+			evidence += ", instance types " + strings.Join(gpuTypes, ", ")
+		}
+		if sc := ng.ScalingConfig; sc != nil {
+			evidence += fmt.Sprintf(", desired %d (min %d, max %d)", aws.ToInt32(sc.DesiredSize), aws.ToInt32(sc.MinSize), aws.ToInt32(sc.MaxSize))
+		}
+		if ng.CapacityType != "" {
+			evidence += ", " + string(ng.CapacityType)
+		}
+
+		findings = append(findings, models.Finding{
+			InstanceID:  cluster + "/" + name,
+			Region:      region,
+			PublicIP:    "N/A",
+			PrivateIP:   "N/A",
+			NameTag:     name,
+			Risk:        models.RiskMedium,
+			Service:     "EKS GPU Node Group",
+			Description: "Managed node group runs GPU or ML accelerator instances",
+			Evidence:    evidence,
+		})
+	}
+
+	return findings, nil
+}
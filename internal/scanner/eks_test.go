@@ -0,0 +1,186 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScanEKS(t *testing.T) {
+	svc := &fake.EKS{Clusters: []fake.Cluster{
+		{
+			Cluster: ekstypes.Cluster{
+				Name:               aws.String("ml"),
+				Endpoint:           aws.String("https://ml.eks.amazonaws.com"),
+				ResourcesVpcConfig: &ekstypes.VpcConfigResponse{EndpointPublicAccess: true},
+			},
+			Nodegroups: []ekstypes.Nodegroup{
+				{NodegroupName: aws.String("gpu"), InstanceTypes: []string{"g5.2xlarge", "g5.4xlarge"}, AmiType: ekstypes.AMITypesAl2X8664Gpu},
+				{NodegroupName: aws.String("neuron"), AmiType: ekstypes.AMITypesAl2023X8664Neuron},
+				{NodegroupName: aws.String("system"), InstanceTypes: []string{"m6g.large"}, AmiType: ekstypes.AMITypesAl2Arm64},
+			},
+		},
+		{
+			Cluster: ekstypes.Cluster{
+				Name: aws.String("office"),
+				ResourcesVpcConfig: &ekstypes.VpcConfigResponse{
+					EndpointPublicAccess: true,
+					PublicAccessCidrs:    []string{"203.0.113.0/24"},
+				},
+			},
+		},
+		{
+			Cluster: ekstypes.Cluster{
+				Name:               aws.String("private"),
+				ResourcesVpcConfig: &ekstypes.VpcConfigResponse{EndpointPrivateAccess: true},
+			},
+		},
+	}}
+
+	scn := newTestScanner(nil, nil, nil)
+	scn.Client.EKS = svc
+
+	findings, err := scn.ScanEKS(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanEKS: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]models.RiskLevel{
+		{"ml", "EKS Public API Endpoint"}:   models.RiskHigh,
+		{"ml/gpu", "EKS GPU Node Group"}:    models.RiskMedium,
+		{"ml/neuron", "EKS GPU Node Group"}: models.RiskMedium,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k].Risk != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k].Risk, risk)
+		}
+	}
+
+	if ev := got[key{"ml/gpu", "EKS GPU Node Group"}].Evidence; !strings.Contains(ev, "g5.2xlarge, g5.4xlarge") {
+		t.Errorf("node group evidence = %q, want instance types", ev)
+	}
+}
+
+func TestScanWorkloads(t *testing.T) {
+	labels := map[string]string{"app": "ollama"}
+	cs := kubefake.NewClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ollama", Namespace: "llm"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name:  "ollama",
+					Image: "ollama/ollama:0.3.12",
+					Ports: []corev1.ContainerPort{{Name: "api", ContainerPort: 11434}},
+				}}},
+			}},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.27"}}},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "ollama-public", Namespace: "llm"},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeLoadBalancer,
+				Selector: labels,
+				Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("api")}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ollama-internal",
+				Namespace:   "llm",
+				Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal"},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeLoadBalancer,
+				Selector: labels,
+				Ports:    []corev1.ServicePort{{Port: 11434}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Port: 8888, NodePort: 30888}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "office-only", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				Ports:                    []corev1.ServicePort{{Port: 8265}},
+				LoadBalancerSourceRanges: []string{"203.0.113.0/24"},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 443}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "ollama", Namespace: "llm"},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports:    []corev1.ServicePort{{Port: 11434}},
+			},
+		},
+	)
+
+	scn := newTestScanner(nil, nil, nil)
+	findings, err := scn.scanWorkloads(context.Background(), cs, "ml", "us-east-1")
+	if err != nil {
+		t.Fatalf("scanWorkloads: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]models.RiskLevel{
+		{"ml/llm/ollama", "EKS AI Deployment"}:                        models.RiskMedium,
+		{"ml/llm/ollama-public", "Ollama API via EKS LoadBalancer"}:   models.RiskCritical,
+		{"ml/llm/ollama-internal", "Ollama API via EKS LoadBalancer"}: models.RiskMedium,
+		{"ml/default/notebook", "Jupyter Notebook via EKS NodePort"}:  models.RiskMedium,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k].Risk != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k].Risk, risk)
+		}
+	}
+
+	if f := got[key{"ml/llm/ollama-public", "Ollama API via EKS LoadBalancer"}]; f.Port != 11434 || !strings.Contains(f.Evidence, "deployment llm/ollama") {
+		t.Errorf("public service finding = port %d, evidence %q", f.Port, f.Evidence)
+	}
+}
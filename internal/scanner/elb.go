@@ -0,0 +1,338 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// ScanLoadBalancers walks internet-facing ALBs and NLBs down to their
+// registered targets and reports AI ports that are reachable through them.
+// Each target group is scored by the listeners that forward to it. Findings
+// are attributed to the backend instance; IP targets that don't belong to a
+// scanned or excluded instance, such as Fargate tasks, are reported by IP.
+func (s *Scanner) ScanLoadBalancers(ctx context.Context, target *Target) ([]models.Finding, error) {
+	byID := map[string]types.Instance{}
+	byIP := map[string]types.Instance{}
+	for _, inst := range target.Instances {
+		byID[aws.ToString(inst.InstanceId)] = inst
+		for _, ip := range instancePrivateIPs(inst) {
+			byIP[ip] = inst
+		}
+	}
+	// IP targets on excluded instances are skipped, not reported by IP.
+	excludedIPs := map[string]struct{}{}
+	for _, inst := range target.Excluded {
+		for _, ip := range instancePrivateIPs(inst) {
+			excludedIPs[ip] = struct{}{}
+		}
+	}
+
+	ports := map[int32]AIPort{}
+	for _, p := range s.Ports {
+		ports[p.Port] = p
+	}
+
+	var lbs []elbtypes.LoadBalancer
+	paginator := elbv2.NewDescribeLoadBalancersPaginator(s.Client.ELB, &elbv2.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+		for _, lb := range page.LoadBalancers {
+			if lb.Scheme != elbtypes.LoadBalancerSchemeEnumInternetFacing {
+				continue
+			}
+			if lb.Type != elbtypes.LoadBalancerTypeEnumApplication && lb.Type != elbtypes.LoadBalancerTypeEnumNetwork {
+				continue
+			}
+			lbs = append(lbs, lb)
+		}
+	}
+
+	var findings []models.Finding
+	seen := map[string]struct{}{}
+
+	for idx, lb := range lbs {
+		lbName := aws.ToString(lb.LoadBalancerName)
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking load balancer %s (%d/%d)...", lbName, idx+1, len(lbs)))
+
+		routes, err := s.openRoutes(ctx, lb)
+		if err != nil {
+			log.Printf("WARNING: Failed to get listeners for %s: %v", lbName, err)
+			continue
+		}
+		if len(routes) == 0 {
+			continue
+		}
+
+		groups, err := s.lbTargetGroups(ctx, lb)
+		if err != nil {
+			log.Printf("WARNING: Failed to get target groups for %s: %v", lbName, err)
+			continue
+		}
+
+		kind := "ALB"
+		if lb.Type == elbtypes.LoadBalancerTypeEnumNetwork {
+			kind = "NLB"
+		}
+
+		for _, tg := range groups {
+			if tg.TargetType != elbtypes.TargetTypeEnumInstance && tg.TargetType != elbtypes.TargetTypeEnumIp {
+				continue
+			}
+			route, ok := routes[aws.ToString(tg.TargetGroupArn)]
+			if !ok {
+				continue
+			}
+			exp := route.Exposure
+
+			health, err := s.Client.ELB.DescribeTargetHealth(ctx, &elbv2.DescribeTargetHealthInput{
+				TargetGroupArn: tg.TargetGroupArn,
+			})
+			if err != nil {
+				log.Printf("WARNING: Failed to get targets for %s: %v", aws.ToString(tg.TargetGroupName), err)
+				continue
+			}
+
+			for _, desc := range health.TargetHealthDescriptions {
+				if desc.Target == nil {
+					continue
+				}
+				port := aws.ToInt32(tg.Port)
+				if desc.Target.Port != nil {
+					port = *desc.Target.Port
+				}
+				p, ok := ports[port]
+				if !ok {
+					continue
+				}
+
+				id := aws.ToString(desc.Target.Id)
+				inst, ok := byID[id]
+				if !ok && tg.TargetType == elbtypes.TargetTypeEnumIp {
+					if _, skip := excludedIPs[id]; skip {
+						continue
+					}
+					inst, ok = byIP[id]
+				}
+				if !ok && tg.TargetType == elbtypes.TargetTypeEnumInstance {
+					// Stopped or excluded instance.
+					continue
+				}
+
+				f := models.Finding{
+					InstanceID:  id,
+					Region:      target.Region,
+					PublicIP:    "N/A",
+					PrivateIP:   id,
+					NameTag:     "Unknown",
+					Risk:        p.Risk.AtMost(exp.Cap),
+					Service:     fmt.Sprintf("%s via %s", p.Name, kind),
+					Port:        port,
+					Description: fmt.Sprintf("%s reachable through internet-facing %s %s", p.Name, kind, lbName),
+				}
+				if ok {
+					f.InstanceID = aws.ToString(inst.InstanceId)
+					f.PublicIP = getPublicIP(inst)
+					f.PrivateIP = getPrivateIP(inst)
+					f.NameTag = getNameTag(inst.Tags)
+				}
+
+				key := fmt.Sprintf("%s:%s:%d", aws.ToString(lb.LoadBalancerArn), f.InstanceID, port)
+				if _, dup := seen[key]; dup {
+					continue
+				}
+				seen[key] = struct{}{}
+
+				state := "unknown"
+				if desc.TargetHealth != nil {
+					state = string(desc.TargetHealth.State)
+				}
+				f.Evidence = fmt.Sprintf("%s (%s) -> target group %s -> %s:%d [%s]",
+					aws.ToString(lb.DNSName), strings.Join(route.Listeners, ", "), aws.ToString(tg.TargetGroupName), id, port, state)
+				if len(lb.SecurityGroups) > 0 {
+					f.Evidence += "; LB security group open to " + exp.Source
+				}
+
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// lbRoute is the internet-reachable listeners that forward to one target
+// group, and the widest exposure among them.
+type lbRoute struct {
+	Listeners []string
+	Exposure  exposure
+}
+
+// openRoutes maps target group ARNs to the listeners reachable from the
+// internet, as "PROTOCOL:port", whose default actions or rules forward to
+// them. Load balancers without security groups, like most NLBs, accept
+// traffic from anywhere.
+func (s *Scanner) openRoutes(ctx context.Context, lb elbtypes.LoadBalancer) (map[string]*lbRoute, error) {
+	var listeners []elbtypes.Listener
+	paginator := elbv2.NewDescribeListenersPaginator(s.Client.ELB, &elbv2.DescribeListenersInput{
+		LoadBalancerArn: lb.LoadBalancerArn,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, page.Listeners...)
+	}
+
+	world := exposure{Cap: models.RiskCritical, Source: "0.0.0.0/0", World: true, V4: true}
+	switch lb.IpAddressType {
+	case elbtypes.IpAddressTypeDualstack:
+		world.Source, world.V6 = "0.0.0.0/0 + ::/0", true
+	case elbtypes.IpAddressTypeDualstackWithoutPublicIpv4:
+		world.Source, world.V4, world.V6 = "::/0", false, true
+	}
+
+	routes := map[string]*lbRoute{}
+	for _, l := range listeners {
+		port := aws.ToInt32(l.Port)
+		exp := world
+		if len(lb.SecurityGroups) > 0 {
+			exp = s.widestGroupExposure(ctx, lb.SecurityGroups, port)
+		}
+		if exp.Cap == "" {
+			continue
+		}
+
+		actions := l.DefaultActions
+		if lb.Type == elbtypes.LoadBalancerTypeEnumApplication {
+			rules, err := s.listenerRules(ctx, aws.ToString(l.ListenerArn))
+			if err != nil {
+				log.Printf("WARNING: Failed to get rules for listener %s: %v", aws.ToString(l.ListenerArn), err)
+			}
+			for _, r := range rules {
+				actions = append(actions, r.Actions...)
+			}
+		}
+
+		name := fmt.Sprintf("%s:%d", l.Protocol, port)
+		for _, arn := range forwardedGroups(actions) {
+			route, ok := routes[arn]
+			if !ok {
+				route = &lbRoute{}
+				routes[arn] = route
+			}
+			if !slices.Contains(route.Listeners, name) {
+				route.Listeners = append(route.Listeners, name)
+			}
+			if exp.Cap.Rank() > route.Exposure.Cap.Rank() {
+				route.Exposure = exp
+			}
+		}
+	}
+
+	for _, route := range routes {
+		slices.Sort(route.Listeners)
+	}
+	return routes, nil
+}
+
+func (s *Scanner) listenerRules(ctx context.Context, listenerARN string) ([]elbtypes.Rule, error) {
+	var rules []elbtypes.Rule
+	paginator := elbv2.NewDescribeRulesPaginator(s.Client.ELB, &elbv2.DescribeRulesInput{
+		ListenerArn: aws.String(listenerARN),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, page.Rules...)
+	}
+	return rules, nil
+}
+
+// forwardedGroups returns the target group ARNs that forward actions send
+// traffic to, including weighted forwards.
+func forwardedGroups(actions []elbtypes.Action) []string {
+	var arns []string
+	for _, a := range actions {
+		if a.Type != elbtypes.ActionTypeEnumForward {
+			continue
+		}
+		if a.TargetGroupArn != nil {
+			arns = append(arns, *a.TargetGroupArn)
+		}
+		if a.ForwardConfig != nil {
+			for _, tg := range a.ForwardConfig.TargetGroups {
+				if tg.TargetGroupArn != nil {
+					arns = append(arns, *tg.TargetGroupArn)
+				}
+			}
+		}
+	}
+	return arns
+}
+
+// widestGroupExposure scores the rules of groups that cover port.
+func (s *Scanner) widestGroupExposure(ctx context.Context, groupIDs []string, port int32) exposure {
+	var widest exposure
+	for _, groupID := range groupIDs {
+		rules, err := s.getSecurityGroupRulesCached(ctx, groupID)
+		if err != nil {
+			log.Printf("WARNING: Failed to get rules for SG %s: %v", groupID, err)
+			continue
+		}
+		for _, rule := range rules {
+			if !isTCPOrAll(rule) || !ruleCoversPort(rule, port) {
+				continue
+			}
+			if exp := s.ruleExposure(ctx, rule, s.sgOwners[groupID]); exp.Cap.Rank() > widest.Cap.Rank() {
+				widest = exp
+			}
+		}
+	}
+	return widest
+}
+
+func (s *Scanner) lbTargetGroups(ctx context.Context, lb elbtypes.LoadBalancer) ([]elbtypes.TargetGroup, error) {
+	var groups []elbtypes.TargetGroup
+	paginator := elbv2.NewDescribeTargetGroupsPaginator(s.Client.ELB, &elbv2.DescribeTargetGroupsInput{
+		LoadBalancerArn: lb.LoadBalancerArn,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, page.TargetGroups...)
+	}
+	return groups, nil
+}
+
+func instancePrivateIPs(inst types.Instance) []string {
+	var ips []string
+	if ip := aws.ToString(inst.PrivateIpAddress); ip != "" {
+		ips = append(ips, ip)
+	}
+	for _, ni := range inst.NetworkInterfaces {
+		for _, addr := range ni.PrivateIpAddresses {
+			if ip := aws.ToString(addr.PrivateIpAddress); ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return ips
+}
@@ -0,0 +1,251 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+func testLoadBalancer(name string, scheme elbtypes.LoadBalancerSchemeEnum, groups []string, targetType elbtypes.TargetTypeEnum, targetID string, port int32) fake.LoadBalancer {
+	arn := "arn:aws:elasticloadbalancing:us-east-1:111111111111:loadbalancer/app/" + name
+	return fake.LoadBalancer{
+		LB: elbtypes.LoadBalancer{
+			LoadBalancerArn:  aws.String(arn),
+			LoadBalancerName: aws.String(name),
+			DNSName:          aws.String(name + "-123.us-east-1.elb.amazonaws.com"),
+			Scheme:           scheme,
+			Type:             elbtypes.LoadBalancerTypeEnumApplication,
+			SecurityGroups:   groups,
+		},
+		Listeners: []elbtypes.Listener{{
+			ListenerArn:    aws.String(arn + "/443"),
+			Port:           aws.Int32(443),
+			Protocol:       elbtypes.ProtocolEnumHttps,
+			DefaultActions: []elbtypes.Action{forwardTo(arn + "/tg")},
+		}},
+		TargetGroups: []fake.TargetGroup{{
+			Group: elbtypes.TargetGroup{
+				TargetGroupArn:  aws.String(arn + "/tg"),
+				TargetGroupName: aws.String(name + "-tg"),
+				TargetType:      targetType,
+				Port:            aws.Int32(port),
+			},
+			Targets: []elbtypes.TargetHealthDescription{{
+				Target:       &elbtypes.TargetDescription{Id: aws.String(targetID)},
+				TargetHealth: &elbtypes.TargetHealth{State: elbtypes.TargetHealthStateEnumHealthy},
+			}},
+		}},
+	}
+}
+
+func forwardTo(targetGroupARN string) elbtypes.Action {
+	return elbtypes.Action{Type: elbtypes.ActionTypeEnumForward, TargetGroupArn: aws.String(targetGroupARN)}
+}
+
+func TestScanLoadBalancers(t *testing.T) {
+	tests := []struct {
+		name    string
+		lb      fake.LoadBalancer
+		exclude []string
+		want    map[string]models.RiskLevel
+		wantID  string
+		wantEvd string
+	}{
+		{
+			name:    "alb to private ollama instance",
+			lb:      testLoadBalancer("chat", elbtypes.LoadBalancerSchemeEnumInternetFacing, []string{"sg-lb"}, elbtypes.TargetTypeEnumInstance, "i-1", 11434),
+			want:    map[string]models.RiskLevel{"Ollama API via ALB": models.RiskCritical},
+			wantID:  "i-1",
+			wantEvd: "chat-123.us-east-1.elb.amazonaws.com (HTTPS:443) -> target group chat-tg -> i-1:11434 [healthy]",
+		},
+		{
+			name:   "ip target resolved to instance",
+			lb:     testLoadBalancer("vllm", elbtypes.LoadBalancerSchemeEnumInternetFacing, nil, elbtypes.TargetTypeEnumIp, "10.0.0.10", 8000),
+			want:   map[string]models.RiskLevel{"vLLM / FastChat via ALB": models.RiskHigh},
+			wantID: "i-1",
+		},
+		{
+			name:   "unknown ip target reported by ip",
+			lb:     testLoadBalancer("task", elbtypes.LoadBalancerSchemeEnumInternetFacing, nil, elbtypes.TargetTypeEnumIp, "10.0.9.9", 8888),
+			want:   map[string]models.RiskLevel{"Jupyter Notebook via ALB": models.RiskCritical},
+			wantID: "10.0.9.9",
+		},
+		{
+			name:    "ip target on excluded instance",
+			lb:      testLoadBalancer("skip", elbtypes.LoadBalancerSchemeEnumInternetFacing, nil, elbtypes.TargetTypeEnumIp, "10.0.0.10", 11434),
+			exclude: []string{"i-1"},
+			want:    map[string]models.RiskLevel{},
+		},
+		{
+			name: "internal load balancer",
+			lb:   testLoadBalancer("internal", elbtypes.LoadBalancerSchemeEnumInternal, nil, elbtypes.TargetTypeEnumInstance, "i-1", 11434),
+			want: map[string]models.RiskLevel{},
+		},
+		{
+			name: "lb security group restricted",
+			lb:   testLoadBalancer("office", elbtypes.LoadBalancerSchemeEnumInternetFacing, []string{"sg-office"}, elbtypes.TargetTypeEnumInstance, "i-1", 11434),
+			want: map[string]models.RiskLevel{},
+		},
+		{
+			name: "non-ai target port",
+			lb:   testLoadBalancer("web", elbtypes.LoadBalancerSchemeEnumInternetFacing, nil, elbtypes.TargetTypeEnumInstance, "i-1", 80),
+			want: map[string]models.RiskLevel{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ec2 := &fake.EC2{
+				Instances: []types.Instance{testInstance("i-1", "sg-app")},
+				SecurityGroups: []types.SecurityGroup{
+					{GroupId: aws.String("sg-app")},
+					{GroupId: aws.String("sg-lb"), IpPermissions: []types.IpPermission{tcpRule(443, 443, "0.0.0.0/0")}},
+					{GroupId: aws.String("sg-office"), IpPermissions: []types.IpPermission{tcpRule(443, 443, "198.51.100.0/24")}},
+				},
+			}
+			scn := newTestScanner(ec2, nil, nil)
+			scn.ExcludeIDs = tt.exclude
+			scn.Client.ELB = &fake.ELB{LoadBalancers: []fake.LoadBalancer{tt.lb}}
+
+			findings, err := scn.Scan(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+
+			got := findingsByService(findings)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d findings %v, want %d", len(got), got, len(tt.want))
+			}
+			for service, risk := range tt.want {
+				f := got[service]
+				if f.Risk != risk {
+					t.Errorf("%s: risk = %s, want %s", service, f.Risk, risk)
+				}
+				if f.InstanceID != tt.wantID {
+					t.Errorf("%s: instance = %s, want %s", service, f.InstanceID, tt.wantID)
+				}
+				if !strings.Contains(f.Evidence, tt.wantEvd) {
+					t.Errorf("%s: evidence = %q, want %q", service, f.Evidence, tt.wantEvd)
+				}
+			}
+		})
+	}
+}
+
+func TestScanLoadBalancersFollowsListenerRoutes(t *testing.T) {
+	const arn = "arn:aws:elasticloadbalancing:us-east-1:111111111111:loadbalancer/app/mixed"
+	group := func(name string, port int32, target string) fake.TargetGroup {
+		return fake.TargetGroup{
+			Group: elbtypes.TargetGroup{
+				TargetGroupArn:  aws.String(arn + "/" + name),
+				TargetGroupName: aws.String(name),
+				TargetType:      elbtypes.TargetTypeEnumIp,
+				Port:            aws.Int32(port),
+			},
+			Targets: []elbtypes.TargetHealthDescription{{Target: &elbtypes.TargetDescription{Id: aws.String(target)}}},
+		}
+	}
+
+	// 443 is open to the world and serves the web app and, through a
+	// weighted rule, Jupyter. 8443 is office-only and is the sole route to
+	// Ollama.
+	lb := fake.LoadBalancer{
+		LB: elbtypes.LoadBalancer{
+			LoadBalancerArn:  aws.String(arn),
+			LoadBalancerName: aws.String("mixed"),
+			DNSName:          aws.String("mixed-123.us-east-1.elb.amazonaws.com"),
+			Scheme:           elbtypes.LoadBalancerSchemeEnumInternetFacing,
+			Type:             elbtypes.LoadBalancerTypeEnumApplication,
+			SecurityGroups:   []string{"sg-mixed"},
+		},
+		Listeners: []elbtypes.Listener{
+			{ListenerArn: aws.String(arn + "/443"), Port: aws.Int32(443), Protocol: elbtypes.ProtocolEnumHttps, DefaultActions: []elbtypes.Action{forwardTo(arn + "/web")}},
+			{ListenerArn: aws.String(arn + "/8443"), Port: aws.Int32(8443), Protocol: elbtypes.ProtocolEnumHttps, DefaultActions: []elbtypes.Action{{Type: elbtypes.ActionTypeEnumFixedResponse}}},
+		},
+		Rules: map[string][]elbtypes.Rule{
+			arn + "/443": {{Actions: []elbtypes.Action{{
+				Type: elbtypes.ActionTypeEnumForward,
+				ForwardConfig: &elbtypes.ForwardActionConfig{TargetGroups: []elbtypes.TargetGroupTuple{
+					{TargetGroupArn: aws.String(arn + "/notebooks")},
+				}},
+			}}}},
+			arn + "/8443": {{Actions: []elbtypes.Action{forwardTo(arn + "/ollama")}}},
+		},
+		TargetGroups: []fake.TargetGroup{
+			group("web", 8501, "10.0.1.1"),
+			group("notebooks", 8888, "10.0.1.2"),
+			group("ollama", 11434, "10.0.1.3"),
+		},
+	}
+
+	ec2 := &fake.EC2{SecurityGroups: []types.SecurityGroup{{
+		GroupId: aws.String("sg-mixed"),
+		IpPermissions: []types.IpPermission{
+			tcpRule(443, 443, "0.0.0.0/0"),
+			tcpRule(8443, 8443, "44.0.0.0/8"),
+		},
+	}}}
+	scn := newTestScanner(ec2, nil, nil)
+	scn.Client.ELB = &fake.ELB{LoadBalancers: []fake.LoadBalancer{lb}}
+
+	findings, err := scn.ScanLoadBalancers(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanLoadBalancers: %v", err)
+	}
+
+	got := findingsByService(findings)
+	want := map[string]models.RiskLevel{
+		"Streamlit App via ALB":    models.RiskHigh,
+		"Jupyter Notebook via ALB": models.RiskCritical,
+		"Ollama API via ALB":       models.RiskHigh,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings %v, want %d", len(got), got, len(want))
+	}
+	for service, risk := range want {
+		if got[service].Risk != risk {
+			t.Errorf("%s: risk = %s, want %s", service, got[service].Risk, risk)
+		}
+	}
+	if ev := got["Ollama API via ALB"].Evidence; !strings.Contains(ev, "(HTTPS:8443)") || !strings.Contains(ev, "open to 44.0.0.0/8") {
+		t.Errorf("ollama evidence = %q, want only the 8443 listener", ev)
+	}
+}
+
+func TestOpenRoutesDualstackNLB(t *testing.T) {
+	const arn = "arn:aws:elasticloadbalancing:us-east-1:111111111111:loadbalancer/net/dual"
+	lb := fake.LoadBalancer{
+		LB: elbtypes.LoadBalancer{
+			LoadBalancerArn: aws.String(arn),
+			Scheme:          elbtypes.LoadBalancerSchemeEnumInternetFacing,
+			Type:            elbtypes.LoadBalancerTypeEnumNetwork,
+			IpAddressType:   elbtypes.IpAddressTypeDualstack,
+		},
+		Listeners: []elbtypes.Listener{{
+			ListenerArn:    aws.String(arn + "/11434"),
+			Port:           aws.Int32(11434),
+			Protocol:       elbtypes.ProtocolEnumTcp,
+			DefaultActions: []elbtypes.Action{forwardTo(arn + "/tg")},
+		}},
+	}
+	scn := newTestScanner(nil, nil, nil)
+	scn.Client.ELB = &fake.ELB{LoadBalancers: []fake.LoadBalancer{lb}}
+
+	routes, err := scn.openRoutes(context.Background(), lb.LB)
+	if err != nil {
+		t.Fatalf("openRoutes: %v", err)
+	}
+	route := routes[arn+"/tg"]
+	if route == nil {
+		t.Fatalf("no route to target group: %v", routes)
+	}
+	if exp := route.Exposure; exp.Cap != models.RiskCritical || !exp.V4 || !exp.V6 {
+		t.Errorf("exposure = %+v, want CRITICAL on IPv4 and IPv6", exp)
+	}
+}
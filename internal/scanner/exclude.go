@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// TagFilter matches instance tags for exclusion. Key and Value support
+// shell-style wildcards (*, ?). An empty Value matches any value, so a
+// filter with only a key excludes every instance carrying that tag.
+type TagFilter struct {
+	Key   string
+	Value string
+}
+
+// ParseTagFilter parses "key" or "key=value" into a TagFilter.
+func ParseTagFilter(s string) TagFilter {
+	key, value, _ := strings.Cut(strings.TrimSpace(s), "=")
+	return TagFilter{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)}
+}
+
+// TagFiltersFromMap converts a key/value map, as found in the config file,
+// into filters sorted by key.
+func TagFiltersFromMap(tags map[string]string) []TagFilter {
+	filters := make([]TagFilter, 0, len(tags))
+	for k, v := range tags {
+		filters = append(filters, TagFilter{Key: k, Value: v})
+	}
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].Key < filters[j].Key
+	})
+	return filters
+}
+
+func (f TagFilter) String() string {
+	if f.Value == "" {
+		return f.Key
+	}
+	return f.Key + "=" + f.Value
+}
+
+// Matches reports whether any tag satisfies the filter.
+func (f TagFilter) Matches(tags []types.Tag) bool {
+	for _, t := range tags {
+		if !globMatch(f.Key, aws.ToString(t.Key)) {
+			continue
+		}
+		if f.Value == "" || globMatch(f.Value, aws.ToString(t.Value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches s against a pattern where * matches any run of
+// characters (including "/") and ? matches exactly one.
+func globMatch(pattern, s string) bool {
+	p, str := 0, 0
+	star, mark := -1, 0
+	for str < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[str]):
+			p++
+			str++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, str
+			p++
+		case star != -1:
+			p = star + 1
+			mark++
+			str = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// isExcluded reports whether the instance matches one of the scanner's
+// exclusion rules and must not be analyzed.
+func (s *Scanner) isExcluded(instance types.Instance) bool {
+	instanceID := aws.ToString(instance.InstanceId)
+	for _, id := range s.ExcludeIDs {
+		if id == instanceID {
+			return true
+		}
+	}
+	for _, f := range s.ExcludeTags {
+		if f.Matches(instance.Tags) {
+			return true
+		}
+	}
+	return false
+}
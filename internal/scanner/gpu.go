@@ -0,0 +1,282 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/pterm/pterm"
+)
+
+// gpuFamily matches instance types with GPUs (p*, g*, gr*) or AWS ML
+// accelerators (inf*, trn*).
+var gpuFamily = regexp.MustCompile(`^(p|g|gr|inf|trn)\d`)
+
+// dlFamily matches the DL instance types, which carry third-party deep
+// learning chips: Habana Gaudi (dl1) and Qualcomm AI 100 (dl2q).
+var dlFamily = regexp.MustCompile(`^dl\d`)
+
+func isGPUInstanceType(instanceType string) bool {
+	return gpuFamily.MatchString(instanceType)
+}
+
+// isAcceleratedInstanceType also counts the DL types. The inventory and the
+// latent capacity scan use it since DL hosts cost as much as GPU ones; EKS
+// node group detection sticks to isGPUInstanceType.
+func isAcceleratedInstanceType(instanceType string) bool {
+	return isGPUInstanceType(instanceType) || dlFamily.MatchString(instanceType)
+}
+
+// hoursPerMonth is the month length AWS uses for monthly estimates.
+const hoursPerMonth = 730
+
+// gpuIdleThreshold is the average GPU utilization, in percent, below which
+// a running instance counts as idle.
+const gpuIdleThreshold = 5.0
+
+// ownerTagKeys are tag keys, compared case-insensitively, that name who
+// launched or owns an instance.
+var ownerTagKeys = []string{"owner", "createdby", "created-by", "created_by", "creator", "team", "contact"}
+
+// gpuUtilScript samples nvidia-smi five times, two seconds apart, and prints
+// the average utilization across all GPUs.
+const gpuUtilScript = `
+#!/bin/bash
+if command -v nvidia-smi &> /dev/null; then
+	for i in 1 2 3 4 5; do
+		nvidia-smi --query-gpu=utilization.gpu --format=csv,noheader,nounits 2>/dev/null
+		sleep 2
+	done | awk '{ s += $1; n++ } END { if (n) printf "GPU_UTIL|%.1f\n", s / n }'
+fi
+`
+
+// GPUInstance is one GPU or ML accelerator instance in the inventory.
+type GPUInstance struct {
+	InstanceID   string    `json:"instance_id"`
+	Region       string    `json:"region"`
+	Name         string    `json:"name,omitempty"`
+	InstanceType string    `json:"instance_type"`
+	Accelerator  string    `json:"accelerator,omitempty"`
+	GPUs         int       `json:"gpus"`
+	State        string    `json:"state"`
+	LaunchTime   time.Time `json:"launch_time"`
+	Owner        string    `json:"owner,omitempty"`
+
+	// HourlyPrice is the on-demand price from the bundled table, or zero
+	// when the type isn't in it.
+	HourlyPrice float64 `json:"hourly_price"`
+	// MonthlyCost is what the instance costs if it keeps running for a
+	// month. It is zero for stopped instances.
+	MonthlyCost float64 `json:"monthly_cost"`
+
+	// Utilization is the average GPU utilization in percent sampled over
+	// SSM with --deep, or nil when it wasn't sampled.
+	Utilization *float64 `json:"utilization,omitempty"`
+	Idle        bool     `json:"idle"`
+}
+
+// GPUInventory lists every GPU and ML accelerator instance in the region,
+// running or stopped. With s.Deep, the GPU utilization of running instances
+// is sampled over SSM and those below gpuIdleThreshold are marked idle.
+func (s *Scanner) GPUInventory(ctx context.Context, spinner *pterm.SpinnerPrinter) ([]GPUInstance, error) {
+	ui.UpdateSpinner(spinner, fmt.Sprintf("Listing GPU instances in %s...", s.Client.Region))
+
+	paginator := ec2.NewDescribeInstancesPaginator(s.Client.EC2, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("instance-type"), Values: []string{"p*", "g*", "inf*", "trn*", "dl*"}},
+			{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}},
+		},
+	})
+
+	var inventory []GPUInstance
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe GPU instances: %w", err)
+		}
+		for _, r := range page.Reservations {
+			for _, inst := range r.Instances {
+				if !isAcceleratedInstanceType(string(inst.InstanceType)) || s.isExcluded(inst) {
+					continue
+				}
+				inventory = append(inventory, gpuInstance(inst, s.Client.Region))
+			}
+		}
+	}
+
+	if !s.Deep || s.Client.SSM == nil {
+		return inventory, nil
+	}
+
+	var running []string
+	for _, g := range inventory {
+		if g.State == string(types.InstanceStateNameRunning) {
+			running = append(running, g.InstanceID)
+		}
+	}
+	ui.UpdateSpinner(spinner, fmt.Sprintf("Sampling GPU utilization on %d instances (SSM)...", len(running)))
+	util := s.sampleGPUUtilization(ctx, running)
+	for i := range inventory {
+		if u, ok := util[inventory[i].InstanceID]; ok {
+			inventory[i].Utilization = &u
+			inventory[i].Idle = u < gpuIdleThreshold
+		}
+	}
+
+	return inventory, nil
+}
+
+func gpuInstance(inst types.Instance, region string) GPUInstance {
+	instanceType := string(inst.InstanceType)
+	family, _, _ := strings.Cut(instanceType, ".")
+
+	g := GPUInstance{
+		InstanceID:   aws.ToString(inst.InstanceId),
+		Region:       region,
+		InstanceType: instanceType,
+		Accelerator:  gpuAccelerators[family],
+		LaunchTime:   aws.ToTime(inst.LaunchTime),
+	}
+	if inst.State != nil {
+		g.State = string(inst.State.Name)
+	}
+	for _, tag := range inst.Tags {
+		key := strings.ToLower(aws.ToString(tag.Key))
+		switch {
+		case key == "name":
+			g.Name = aws.ToString(tag.Value)
+		case g.Owner == "" && slices.Contains(ownerTagKeys, key):
+			g.Owner = aws.ToString(tag.Value)
+		}
+	}
+	if price, ok := gpuPrices[instanceType]; ok {
+		g.GPUs = price.GPUs
+		g.HourlyPrice = price.Hourly
+		if g.State != string(types.InstanceStateNameStopped) {
+			g.MonthlyCost = price.Hourly * hoursPerMonth
+		}
+	}
+	return g
+}
+
+// sampleGPUUtilization runs gpuUtilScript on the SSM-managed instances among
+// ids and returns the average utilization of those that reported one.
+func (s *Scanner) sampleGPUUtilization(ctx context.Context, ids []string) map[string]float64 {
+	util := map[string]float64{}
+	if len(ids) == 0 {
+		return util
+	}
+
+	targets, _ := s.managedInstances(ctx, ids)
+	invocations, err := s.sendDeepScanCommands(ctx, targets, gpuUtilScript)
+	if err != nil {
+		log.Printf("WARNING: Failed to sample GPU utilization: %v", err)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan ssmInvocation)
+	for w := 0; w < min(ssmPollWorkers, len(invocations)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for inv := range jobs {
+				stdout, status, err := s.waitCommandOutput(ctx, inv.commandID, inv.instanceID, ssmInvocationTimeout)
+				if err != nil || status != ssmtypes.CommandInvocationStatusSuccess {
+					continue
+				}
+				if u, ok := parseGPUUtilization(stdout); ok {
+					mu.Lock()
+					util[inv.instanceID] = u
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, inv := range invocations {
+		jobs <- inv
+	}
+	close(jobs)
+	wg.Wait()
+
+	return util
+}
+
+func parseGPUUtilization(stdout string) (float64, bool) {
+	for _, line := range strings.Split(stdout, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "GPU_UTIL|"); ok {
+			u, err := strconv.ParseFloat(v, 64)
+			return u, err == nil
+		}
+	}
+	return 0, false
+}
+
+// gpuFindings turns the inventory into findings. Running instances are
+// MEDIUM, stopped ones LOW.
+func gpuFindings(inventory []GPUInstance) []models.Finding {
+	var findings []models.Finding
+	for _, g := range inventory {
+		f := models.Finding{
+			InstanceID: g.InstanceID,
+			Region:     g.Region,
+			PublicIP:   "N/A",
+			PrivateIP:  "N/A",
+			NameTag:    g.Name,
+			Risk:       models.RiskMedium,
+			Service:    "GPU Instance",
+			Evidence:   gpuEvidence(g),
+		}
+		switch {
+		case g.Idle:
+			f.Service = "Idle GPU Instance"
+			f.Description = fmt.Sprintf("%s running with GPUs %.1f%% utilized", g.InstanceType, *g.Utilization)
+		case g.State == string(types.InstanceStateNameStopped):
+			f.Risk = models.RiskLow
+			f.Description = fmt.Sprintf("Stopped %s", g.InstanceType)
+		default:
+			f.Description = fmt.Sprintf("%s %s", g.InstanceType, g.State)
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func gpuEvidence(g GPUInstance) string {
+	parts := []string{g.InstanceType}
+	switch {
+	case g.GPUs > 0 && g.Accelerator != "":
+		parts[0] += fmt.Sprintf(" (%dx %s)", g.GPUs, g.Accelerator)
+	case g.Accelerator != "":
+		parts[0] += fmt.Sprintf(" (%s)", g.Accelerator)
+	}
+	if !g.LaunchTime.IsZero() {
+		parts = append(parts, "launched "+g.LaunchTime.UTC().Format("2006-01-02"))
+	}
+	if g.Owner != "" {
+		parts = append(parts, "owner "+g.Owner)
+	}
+	if g.HourlyPrice > 0 {
+		price := fmt.Sprintf("$%.2f/h", g.HourlyPrice)
+		if g.MonthlyCost > 0 {
+			price += fmt.Sprintf(" (~$%.0f/month)", g.MonthlyCost)
+		}
+		parts = append(parts, price)
+	}
+	if g.Utilization != nil {
+		parts = append(parts, fmt.Sprintf("GPU utilization %.1f%%", *g.Utilization))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func gpuTestInstance(id string, instanceType types.InstanceType, state types.InstanceStateName, tags ...string) types.Instance {
+	inst := testInstance(id)
+	inst.InstanceType = instanceType
+	inst.State = &types.InstanceState{Name: state}
+	inst.LaunchTime = aws.Time(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	for i := 0; i+1 < len(tags); i += 2 {
+		inst.Tags = append(inst.Tags, types.Tag{Key: aws.String(tags[i]), Value: aws.String(tags[i+1])})
+	}
+	return inst
+}
+
+func TestGPUInventory(t *testing.T) {
+	defer func(d time.Duration) { ssmPollInterval = d }(ssmPollInterval)
+	ssmPollInterval = time.Millisecond
+
+	ec2 := &fake.EC2{Instances: []types.Instance{
+		gpuTestInstance("i-idle", types.InstanceTypeP4d24xlarge, types.InstanceStateNameRunning, "Name", "llm-train", "CreatedBy", "alice"),
+		gpuTestInstance("i-busy", types.InstanceTypeG5Xlarge, types.InstanceStateNameRunning),
+		gpuTestInstance("i-stopped", types.InstanceTypeInf2Xlarge, types.InstanceStateNameStopped, "Team", "search"),
+		gpuTestInstance("i-unmanaged", types.InstanceTypeDl124xlarge, types.InstanceStateNameRunning),
+		gpuTestInstance("i-cpu", types.InstanceTypeM5Large, types.InstanceStateNameRunning),
+		gpuTestInstance("i-gone", types.InstanceTypeG5Xlarge, types.InstanceStateNameTerminated),
+	}}
+	ssm := &fake.SSM{Outputs: map[string]string{
+		"i-idle": "GPU_UTIL|0.4\n",
+		"i-busy": "GPU_UTIL|87.0\n",
+	}}
+
+	scn := newTestScanner(ec2, ssm, nil)
+	scn.Deep = true
+
+	inventory, err := scn.GPUInventory(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("GPUInventory: %v", err)
+	}
+
+	got := map[string]GPUInstance{}
+	for _, g := range inventory {
+		got[g.InstanceID] = g
+	}
+	if len(got) != 4 {
+		t.Fatalf("got %d GPU instances, want 4: %v", len(got), got)
+	}
+
+	idle := got["i-idle"]
+	if !idle.Idle || idle.GPUs != 8 || idle.Accelerator != "NVIDIA A100" || idle.Owner != "alice" || idle.Name != "llm-train" {
+		t.Errorf("i-idle = %+v", idle)
+	}
+	if idle.MonthlyCost < 23923 || idle.MonthlyCost > 23924 {
+		t.Errorf("i-idle monthly cost = %.2f, want 730h at $32.7726", idle.MonthlyCost)
+	}
+	if busy := got["i-busy"]; busy.Idle || busy.Utilization == nil || *busy.Utilization != 87 {
+		t.Errorf("i-busy = %+v, want sampled and not idle", busy)
+	}
+	if stopped := got["i-stopped"]; stopped.MonthlyCost != 0 || stopped.HourlyPrice == 0 || stopped.Owner != "search" || stopped.Utilization != nil {
+		t.Errorf("i-stopped = %+v, want price but no monthly cost or sample", stopped)
+	}
+	if unmanaged := got["i-unmanaged"]; unmanaged.Utilization != nil || unmanaged.Idle {
+		t.Errorf("i-unmanaged = %+v, want unsampled", unmanaged)
+	}
+
+	findings := map[string]models.Finding{}
+	for _, f := range gpuFindings(inventory) {
+		findings[f.InstanceID] = f
+	}
+	if f := findings["i-idle"]; f.Service != "Idle GPU Instance" || f.Risk != models.RiskMedium || !strings.Contains(f.Evidence, "owner alice") {
+		t.Errorf("idle finding = %+v", f)
+	}
+	if f := findings["i-stopped"]; f.Service != "GPU Instance" || f.Risk != models.RiskLow {
+		t.Errorf("stopped finding = %+v", f)
+	}
+}
+
+func TestIsGPUInstanceType(t *testing.T) {
+	tests := []struct {
+		instanceType     string
+		gpu, accelerated bool
+	}{
+		{"p5.48xlarge", true, true},
+		{"g5.xlarge", true, true},
+		{"g6e.12xlarge", true, true},
+		{"gr6.4xlarge", true, true},
+		{"inf2.xlarge", true, true},
+		{"trn1.32xlarge", true, true},
+		{"dl1.24xlarge", false, true},
+		{"dl2q.24xlarge", false, true},
+		{"m6g.large", false, false},
+		{"c7g.xlarge", false, false},
+		{"t3.medium", false, false},
+	}
+	for _, tt := range tests {
+		if got := isGPUInstanceType(tt.instanceType); got != tt.gpu {
+			t.Errorf("isGPUInstanceType(%q) = %v, want %v", tt.instanceType, got, tt.gpu)
+		}
+		if got := isAcceleratedInstanceType(tt.instanceType); got != tt.accelerated {
+			t.Errorf("isAcceleratedInstanceType(%q) = %v, want %v", tt.instanceType, got, tt.accelerated)
+		}
+	}
+}
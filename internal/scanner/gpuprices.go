@@ -0,0 +1,123 @@
+package scanner
+
+// gpuType is the accelerator count and on-demand price of a GPU instance
+// type.
+type gpuType struct {
+	GPUs   int
+	Hourly float64
+}
+
+// gpuAccelerators names the accelerator of each GPU instance family.
+var gpuAccelerators = map[string]string{
+	"p2":    "NVIDIA K80",
+	"p3":    "NVIDIA V100",
+	"p3dn":  "NVIDIA V100",
+	"p4d":   "NVIDIA A100",
+	"p4de":  "NVIDIA A100 80GB",
+	"p5":    "NVIDIA H100",
+	"p5e":   "NVIDIA H200",
+	"p5en":  "NVIDIA H200",
+	"g3":    "NVIDIA M60",
+	"g3s":   "NVIDIA M60",
+	"g4dn":  "NVIDIA T4",
+	"g4ad":  "AMD Radeon Pro V520",
+	"g5":    "NVIDIA A10G",
+	"g5g":   "NVIDIA T4G",
+	"g6":    "NVIDIA L4",
+	"gr6":   "NVIDIA L4",
+	"g6e":   "NVIDIA L40S",
+	"inf1":  "AWS Inferentia",
+	"inf2":  "AWS Inferentia2",
+	"trn1":  "AWS Trainium",
+	"trn1n": "AWS Trainium",
+	"dl1":   "Habana Gaudi",
+	"dl2q":  "Qualcomm AI 100",
+}
+
+// gpuPrices are us-east-1 Linux on-demand hourly prices in USD, as published
+// in early 2025. Most other regions charge more, so costs derived from them
+// are estimates. Types missing from the table are reported without a price.
+var gpuPrices = map[string]gpuType{
+	"p2.xlarge":     {1, 0.90},
+	"p2.8xlarge":    {8, 7.20},
+	"p2.16xlarge":   {16, 14.40},
+	"p3.2xlarge":    {1, 3.06},
+	"p3.8xlarge":    {4, 12.24},
+	"p3.16xlarge":   {8, 24.48},
+	"p3dn.24xlarge": {8, 31.212},
+	"p4d.24xlarge":  {8, 32.7726},
+	"p4de.24xlarge": {8, 40.9657},
+	"p5.48xlarge":   {8, 98.32},
+
+	"g3s.xlarge":  {1, 0.75},
+	"g3.4xlarge":  {1, 1.14},
+	"g3.8xlarge":  {2, 2.28},
+	"g3.16xlarge": {4, 4.56},
+
+	"g4dn.xlarge":   {1, 0.526},
+	"g4dn.2xlarge":  {1, 0.752},
+	"g4dn.4xlarge":  {1, 1.204},
+	"g4dn.8xlarge":  {1, 2.176},
+	"g4dn.12xlarge": {4, 3.912},
+	"g4dn.16xlarge": {1, 4.352},
+	"g4dn.metal":    {8, 7.824},
+
+	"g4ad.xlarge":   {1, 0.37853},
+	"g4ad.2xlarge":  {1, 0.54117},
+	"g4ad.4xlarge":  {1, 0.867},
+	"g4ad.8xlarge":  {2, 1.734},
+	"g4ad.16xlarge": {4, 3.468},
+
+	"g5.xlarge":   {1, 1.006},
+	"g5.2xlarge":  {1, 1.212},
+	"g5.4xlarge":  {1, 1.624},
+	"g5.8xlarge":  {1, 2.448},
+	"g5.12xlarge": {4, 5.672},
+	"g5.16xlarge": {1, 4.096},
+	"g5.24xlarge": {4, 8.144},
+	"g5.48xlarge": {8, 16.288},
+
+	"g5g.xlarge":   {1, 0.42},
+	"g5g.2xlarge":  {1, 0.556},
+	"g5g.4xlarge":  {1, 0.828},
+	"g5g.8xlarge":  {1, 1.372},
+	"g5g.16xlarge": {2, 2.744},
+	"g5g.metal":    {2, 2.744},
+
+	"g6.xlarge":   {1, 0.8048},
+	"g6.2xlarge":  {1, 0.9776},
+	"g6.4xlarge":  {1, 1.3232},
+	"g6.8xlarge":  {1, 2.0144},
+	"g6.12xlarge": {4, 4.6016},
+	"g6.16xlarge": {1, 3.3968},
+	"g6.24xlarge": {4, 6.6752},
+	"g6.48xlarge": {8, 13.3504},
+
+	"gr6.4xlarge": {1, 1.5384},
+	"gr6.8xlarge": {1, 2.4464},
+
+	"g6e.xlarge":   {1, 1.861},
+	"g6e.2xlarge":  {1, 2.24208},
+	"g6e.4xlarge":  {1, 3.00424},
+	"g6e.8xlarge":  {1, 4.52856},
+	"g6e.12xlarge": {4, 10.49264},
+	"g6e.16xlarge": {1, 7.57719},
+	"g6e.24xlarge": {4, 15.06559},
+	"g6e.48xlarge": {8, 30.13118},
+
+	"inf1.xlarge":   {1, 0.228},
+	"inf1.2xlarge":  {1, 0.362},
+	"inf1.6xlarge":  {4, 1.18},
+	"inf1.24xlarge": {16, 4.721},
+
+	"inf2.xlarge":   {1, 0.7582},
+	"inf2.8xlarge":  {1, 1.9679},
+	"inf2.24xlarge": {6, 6.4906},
+	"inf2.48xlarge": {12, 12.9813},
+
+	"trn1.2xlarge":   {1, 1.34375},
+	"trn1.32xlarge":  {16, 21.50},
+	"trn1n.32xlarge": {16, 24.78},
+
+	"dl1.24xlarge": {8, 13.10904},
+}
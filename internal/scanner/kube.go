@@ -0,0 +1,268 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubeRequestTimeout bounds each Kubernetes API request so an unreachable
+// private endpoint doesn't stall the region.
+const kubeRequestTimeout = 30 * time.Second
+
+// internalLBAnnotations mark a LoadBalancer Service as VPC-internal.
+var internalLBAnnotations = map[string]string{
+	"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+	"service.beta.kubernetes.io/aws-load-balancer-scheme":   "internal",
+}
+
+// kubeClientForEndpoint returns a clientset for the kubeconfig context whose
+// cluster server is endpoint, or nil when the kubeconfig has none.
+func kubeClientForEndpoint(path, endpoint string) (kubernetes.Interface, error) {
+	if endpoint == "" {
+		return nil, nil
+	}
+	cfg, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Contexts)) {
+		cluster, ok := cfg.Clusters[cfg.Contexts[name].Cluster]
+		if !ok || strings.TrimSuffix(cluster.Server, "/") != strings.TrimSuffix(endpoint, "/") {
+			continue
+		}
+		rc, err := clientcmd.NewNonInteractiveClientConfig(*cfg, name, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", name, err)
+		}
+		rc.Timeout = kubeRequestTimeout
+		return kubernetes.NewForConfig(rc)
+	}
+	return nil, nil
+}
+
+// aiDeployment is a Deployment running a known AI image.
+type aiDeployment struct {
+	Deployment appsv1.Deployment
+	Container  corev1.Container
+	Image      aiImage
+}
+
+// scanWorkloads reports Deployments running AI images and LoadBalancer or
+// NodePort Services that expose an AI port, either from the port catalogue
+// or served by one of those Deployments.
+func (s *Scanner) scanWorkloads(ctx context.Context, cs kubernetes.Interface, cluster, region string) ([]models.Finding, error) {
+	deployments, err := listDeployments(ctx, cs)
+	if err != nil {
+		return nil, fmt.Errorf("list deployments: %w", err)
+	}
+	services, err := listServices(ctx, cs)
+	if err != nil {
+		return nil, fmt.Errorf("list services: %w", err)
+	}
+
+	var findings []models.Finding
+	var ai []aiDeployment
+
+	for _, d := range deployments {
+		for _, c := range d.Spec.Template.Spec.Containers {
+			img, ok := matchAIImage(c.Image)
+			if !ok {
+				continue
+			}
+			ai = append(ai, aiDeployment{Deployment: d, Container: c, Image: img})
+			findings = append(findings, models.Finding{
+				InstanceID:  fmt.Sprintf("%s/%s/%s", cluster, d.Namespace, d.Name),
+				Region:      region,
+				PublicIP:    "N/A",
+				PrivateIP:   "N/A",
+				NameTag:     d.Name,
+				Risk:        models.RiskMedium,
+				Service:     "EKS AI Deployment",
+				Description: fmt.Sprintf("%s running in EKS", img.Name),
+				Evidence: fmt.Sprintf("Deployment %s/%s container %s runs %s (%d replicas)",
+					d.Namespace, d.Name, c.Name, c.Image, replicas(d)),
+			})
+		}
+	}
+
+	ports := map[int32]AIPort{}
+	for _, p := range s.Ports {
+		ports[p.Port] = p
+	}
+
+	for _, svc := range services {
+		kind := svc.Spec.Type
+		if kind != corev1.ServiceTypeLoadBalancer && kind != corev1.ServiceTypeNodePort {
+			continue
+		}
+		capRisk, source, ok := serviceExposure(svc)
+		if !ok {
+			continue
+		}
+		backends := selectedDeployments(svc, ai)
+
+		for _, sp := range svc.Spec.Ports {
+			target := targetPort(sp, backends)
+			p, ok := ports[target]
+			if !ok {
+				if len(backends) == 0 {
+					continue
+				}
+				p = AIPort{Port: target, Name: backends[0].Image.Name, Risk: models.RiskHigh}
+			}
+
+			evidence := fmt.Sprintf("Service %s/%s (%s) port %d -> %d", svc.Namespace, svc.Name, kind, sp.Port, target)
+			if sp.NodePort != 0 {
+				evidence += fmt.Sprintf(", nodePort %d", sp.NodePort)
+			}
+			evidence += ", " + source
+			if len(backends) > 0 {
+				evidence += fmt.Sprintf(" -> deployment %s/%s", backends[0].Deployment.Namespace, backends[0].Deployment.Name)
+			}
+
+			findings = append(findings, models.Finding{
+				InstanceID:  fmt.Sprintf("%s/%s/%s", cluster, svc.Namespace, svc.Name),
+				Region:      region,
+				PublicIP:    "N/A",
+				PrivateIP:   "N/A",
+				NameTag:     svc.Name,
+				Risk:        p.Risk.AtMost(capRisk),
+				Service:     fmt.Sprintf("%s via EKS %s", p.Name, kind),
+				Port:        target,
+				Description: fmt.Sprintf("%s exposed by a Kubernetes %s Service", p.Name, kind),
+				Evidence:    evidence,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// serviceExposure caps the risk of a LoadBalancer or NodePort Service.
+// Internal load balancers and NodePorts, which depend on the node security
+// groups, are capped at MEDIUM; source ranges are scored like security
+// group rules.
+func serviceExposure(svc corev1.Service) (models.RiskLevel, string, bool) {
+	if svc.Spec.Type == corev1.ServiceTypeNodePort {
+		return models.RiskMedium, "reachable where node security groups allow", true
+	}
+	for k, v := range internalLBAnnotations {
+		if strings.EqualFold(svc.Annotations[k], v) {
+			return models.RiskMedium, "internal load balancer", true
+		}
+	}
+
+	host := "pending"
+	for _, ing := range svc.Status.LoadBalancer.Ingress {
+		if h := ing.Hostname; h != "" {
+			host = h
+		} else if ing.IP != "" {
+			host = ing.IP
+		}
+	}
+
+	if ranges := svc.Spec.LoadBalancerSourceRanges; len(ranges) > 0 {
+		exp := mixedCIDRExposure(ranges)
+		if exp.Cap == "" {
+			return "", "", false
+		}
+		return exp.Cap, fmt.Sprintf("load balancer %s open to %s", host, exp.Source), true
+	}
+	return models.RiskCritical, fmt.Sprintf("load balancer %s open to 0.0.0.0/0", host), true
+}
+
+// selectedDeployments returns the AI deployments whose pods the Service
+// selects.
+func selectedDeployments(svc corev1.Service, ai []aiDeployment) []aiDeployment {
+	if len(svc.Spec.Selector) == 0 {
+		return nil
+	}
+	var matched []aiDeployment
+	for _, d := range ai {
+		if d.Deployment.Namespace != svc.Namespace {
+			continue
+		}
+		labels := d.Deployment.Spec.Template.Labels
+		selected := true
+		for k, v := range svc.Spec.Selector {
+			if labels[k] != v {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
+
+// targetPort resolves the container port a Service port forwards to. Named
+// target ports are looked up in the selected containers.
+func targetPort(sp corev1.ServicePort, backends []aiDeployment) int32 {
+	switch {
+	case sp.TargetPort.Type == intstr.Int && sp.TargetPort.IntVal != 0:
+		return sp.TargetPort.IntVal
+	case sp.TargetPort.Type == intstr.String && sp.TargetPort.StrVal != "":
+		for _, b := range backends {
+			for _, cp := range b.Container.Ports {
+				if cp.Name == sp.TargetPort.StrVal {
+					return cp.ContainerPort
+				}
+			}
+		}
+	}
+	return sp.Port
+}
+
+func replicas(d appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
+
+func listDeployments(ctx context.Context, cs kubernetes.Interface) ([]appsv1.Deployment, error) {
+	var items []appsv1.Deployment
+	opts := metav1.ListOptions{Limit: 500}
+	for {
+		list, err := cs.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, list.Items...)
+		if list.Continue == "" {
+			return items, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
+
+func listServices(ctx context.Context, cs kubernetes.Interface) ([]corev1.Service, error) {
+	var items []corev1.Service
+	opts := metav1.ListOptions{Limit: 500}
+	for {
+		list, err := cs.CoreV1().Services(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, list.Items...)
+		if list.Continue == "" {
+			return items, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
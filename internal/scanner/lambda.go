@@ -0,0 +1,231 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// lambdaLargeImageBytes is the container image size above which a function
+// likely bundles model weights; typical Lambda images are a few hundred MB.
+const lambdaLargeImageBytes = 2 << 30
+
+// aiSDKNames are package names that show a function calls or runs models,
+// matched against layer names, image repositories and handlers.
+var aiSDKNames = []string{
+	"openai", "anthropic", "langchain", "llama", "transformers", "torch",
+	"tensorflow", "huggingface", "cohere", "mistral", "vllm", "onnx", "tiktoken",
+}
+
+// ScanLambda checks every function in the region for LLM provider keys in
+// its environment, AI SDKs in its layers, image or handler, public function
+// URLs in front of those, and container images large enough to hold model
+// weights.
+func (s *Scanner) ScanLambda(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var functions []lambdatypes.FunctionConfiguration
+	paginator := lambda.NewListFunctionsPaginator(s.Client.Lambda, &lambda.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list functions: %w", err)
+		}
+		functions = append(functions, page.Functions...)
+	}
+
+	var findings []models.Finding
+	for idx, fn := range functions {
+		name := aws.ToString(fn.FunctionName)
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking Lambda function %s (%d/%d)...", name, idx+1, len(functions)))
+
+		base := models.Finding{InstanceID: name, Region: target.Region, PublicIP: "N/A", PrivateIP: "N/A", NameTag: name}
+
+		keys := lambdaAPIKeys(fn)
+		for _, key := range keys {
+			f := base
+			f.Risk = models.RiskCritical
+			f.Service = "Exposed API Key"
+			f.Description = "API key found in Lambda environment variables"
+			f.Evidence = maskAPIKey(key)
+			findings = append(findings, f)
+		}
+
+		var image string
+		var imageSize int64
+		if fn.PackageType == lambdatypes.PackageTypeImage {
+			out, err := s.Client.Lambda.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: fn.FunctionName})
+			if err != nil {
+				log.Printf("WARNING: Failed to get function %s: %v", name, err)
+			} else if out.Code != nil {
+				image = aws.ToString(out.Code.ResolvedImageUri)
+				if image == "" {
+					image = aws.ToString(out.Code.ImageUri)
+				}
+				imageSize = s.imageSize(ctx, image)
+			}
+		}
+
+		sdks := lambdaAISDKs(fn, image)
+		if len(sdks) > 0 {
+			f := base
+			f.Risk = models.RiskMedium
+			f.Service = "Lambda AI SDK"
+			f.Description = fmt.Sprintf("Function packages %d AI/ML SDKs", len(sdks))
+			f.Evidence = strings.Join(sdks, ", ")
+			findings = append(findings, f)
+		}
+
+		if imageSize >= lambdaLargeImageBytes {
+			f := base
+			f.Risk = models.RiskMedium
+			f.Service = "Lambda Large Container Image"
+			f.Description = "Function image is large enough to bundle model weights"
+			f.Evidence = fmt.Sprintf("%s is %.1f GB", image, float64(imageSize)/(1<<30))
+			findings = append(findings, f)
+		}
+
+		// Only functions that talk to models are worth a URL lookup.
+		if len(keys) == 0 && len(sdks) == 0 {
+			continue
+		}
+		urls, err := s.publicFunctionURLs(ctx, fn)
+		if err != nil {
+			log.Printf("WARNING: Failed to list function URLs for %s: %v", name, err)
+			continue
+		}
+		for _, url := range urls {
+			f := base
+			f.Risk = models.RiskHigh
+			f.Service = "Lambda Public AI Function URL"
+			f.Description = "Anyone can invoke the function and spend its model quota"
+			f.Evidence = fmt.Sprintf("%s has AuthType=NONE", url)
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+// lambdaAPIKeys returns the environment entries, as NAME=value, that hold
+// LLM provider keys.
+func lambdaAPIKeys(fn lambdatypes.FunctionConfiguration) []string {
+	if fn.Environment == nil {
+		return nil
+	}
+	var keys []string
+	for name, value := range fn.Environment.Variables {
+		if isAPIKeyEntry(name, value) {
+			keys = append(keys, name+"="+value)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// lambdaAISDKs returns the layers, image and handler whose names mention an
+// AI SDK, each labelled with where it was found.
+func lambdaAISDKs(fn lambdatypes.FunctionConfiguration, image string) []string {
+	var found []string
+	for _, l := range fn.Layers {
+		if name := layerName(aws.ToString(l.Arn)); mentionsAISDK(name) {
+			found = append(found, "layer "+name)
+		}
+	}
+	if repo := imageRepository(image); mentionsAISDK(repo) {
+		found = append(found, "image "+repo)
+	}
+	if h := aws.ToString(fn.Handler); mentionsAISDK(h) {
+		found = append(found, "handler "+h)
+	}
+	return found
+}
+
+func mentionsAISDK(name string) bool {
+	name = strings.ToLower(name)
+	for _, sdk := range aiSDKNames {
+		if strings.Contains(name, sdk) {
+			return true
+		}
+	}
+	return false
+}
+
+// layerName returns NAME from arn:aws:lambda:region:account:layer:NAME:version.
+func layerName(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 {
+		return arn
+	}
+	return parts[6]
+}
+
+func (s *Scanner) publicFunctionURLs(ctx context.Context, fn lambdatypes.FunctionConfiguration) ([]string, error) {
+	var urls []string
+	paginator := lambda.NewListFunctionUrlConfigsPaginator(s.Client.Lambda, &lambda.ListFunctionUrlConfigsInput{
+		FunctionName: fn.FunctionName,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page.FunctionUrlConfigs {
+			if u.AuthType == lambdatypes.FunctionUrlAuthTypeNone {
+				urls = append(urls, aws.ToString(u.FunctionUrl))
+			}
+		}
+	}
+	return urls, nil
+}
+
+// imageSize returns the size of an ECR image given as
+// account.dkr.ecr.region.amazonaws.com/repo@sha256:digest, or 0 when it
+// can't be looked up.
+func (s *Scanner) imageSize(ctx context.Context, image string) int64 {
+	if s.Client.ECR == nil {
+		return 0
+	}
+	registry, rest, ok := strings.Cut(image, ".dkr.ecr.")
+	if !ok {
+		return 0
+	}
+	_, ref, _ := strings.Cut(rest, "/")
+	repo, digest, ok := strings.Cut(ref, "@")
+	if !ok {
+		return 0
+	}
+
+	out, err := s.Client.ECR.DescribeImages(ctx, &ecr.DescribeImagesInput{
+		RegistryId:     aws.String(registry),
+		RepositoryName: aws.String(repo),
+		ImageIds:       []ecrtypes.ImageIdentifier{{ImageDigest: aws.String(digest)}},
+	})
+	if err != nil || len(out.ImageDetails) == 0 {
+		return 0
+	}
+	return aws.ToInt64(out.ImageDetails[0].ImageSizeInBytes)
+}
+
+// imageRepository returns the repository path of an image URI without the
+// registry host, tag or digest.
+func imageRepository(image string) string {
+	if image == "" {
+		return ""
+	}
+	if _, rest, ok := strings.Cut(image, "/"); ok {
+		image = rest
+	}
+	if i := strings.IndexAny(image, "@:"); i >= 0 {
+		image = image[:i]
+	}
+	return image
+}
@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+func TestScanLambda(t *testing.T) {
+	const image = "111111111111.dkr.ecr.us-east-1.amazonaws.com/llama-summarizer@sha256:abc"
+
+	lam := &fake.Lambda{Functions: []fake.Function{
+		{
+			Config: lambdatypes.FunctionConfiguration{
+				FunctionName: aws.String("chatbot"),
+				Handler:      aws.String("app.handler"),
+				Environment: &lambdatypes.EnvironmentResponse{Variables: map[string]string{
+					"OPENAI_API_KEY":  "sk-abcdefghijklmnop",
+					"OPENAI_BASE_URL": "https://api.openai.com/v1",
+					"LLM_PROVIDER":    "anthropic",
+					"TABLE_NAME":      "sessions",
+				}},
+				Layers: []lambdatypes.Layer{{Arn: aws.String("arn:aws:lambda:us-east-1:111111111111:layer:langchain-py312:4")}},
+			},
+			URLs: []lambdatypes.FunctionUrlConfig{
+				{FunctionUrl: aws.String("https://chat.lambda-url.us-east-1.on.aws/"), AuthType: lambdatypes.FunctionUrlAuthTypeNone},
+			},
+		},
+		{
+			Config: lambdatypes.FunctionConfiguration{
+				FunctionName: aws.String("summarizer"),
+				PackageType:  lambdatypes.PackageTypeImage,
+			},
+			Code: &lambdatypes.FunctionCodeLocation{ResolvedImageUri: aws.String(image)},
+			URLs: []lambdatypes.FunctionUrlConfig{
+				{FunctionUrl: aws.String("https://sum.lambda-url.us-east-1.on.aws/"), AuthType: lambdatypes.FunctionUrlAuthTypeAwsIam},
+			},
+		},
+		{
+			// A public URL alone is not an AI finding.
+			Config: lambdatypes.FunctionConfiguration{FunctionName: aws.String("webhook"), Handler: aws.String("index.handler")},
+			URLs: []lambdatypes.FunctionUrlConfig{
+				{FunctionUrl: aws.String("https://hook.lambda-url.us-east-1.on.aws/"), AuthType: lambdatypes.FunctionUrlAuthTypeNone},
+			},
+		},
+	}}
+	registry := &fake.ECR{Images: map[string]ecrtypes.ImageDetail{
+		"llama-summarizer@sha256:abc": {ImageSizeInBytes: aws.Int64(6 << 30)},
+	}}
+
+	scn := newTestScanner(nil, nil, nil)
+	scn.Client.Lambda = lam
+	scn.Client.ECR = registry
+
+	findings, err := scn.ScanLambda(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanLambda: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]models.RiskLevel{
+		{"chatbot", "Exposed API Key"}:                 models.RiskCritical,
+		{"chatbot", "Lambda AI SDK"}:                   models.RiskMedium,
+		{"chatbot", "Lambda Public AI Function URL"}:   models.RiskHigh,
+		{"summarizer", "Lambda AI SDK"}:                models.RiskMedium,
+		{"summarizer", "Lambda Large Container Image"}: models.RiskMedium,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k].Risk != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k].Risk, risk)
+		}
+	}
+
+	if ev := got[key{"chatbot", "Exposed API Key"}].Evidence; ev != "OPENAI_API_KEY=sk-a***mnop" {
+		t.Errorf("key evidence = %q, want masked key", ev)
+	}
+	if ev := got[key{"summarizer", "Lambda AI SDK"}].Evidence; !strings.Contains(ev, "image llama-summarizer") {
+		t.Errorf("SDK evidence = %q, want image repository", ev)
+	}
+}
+
+func TestImageRepository(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"111111111111.dkr.ecr.us-east-1.amazonaws.com/team/llama-server@sha256:abc", "team/llama-server"},
+		{"111111111111.dkr.ecr.us-east-1.amazonaws.com/app:latest", "app"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := imageRepository(tt.image); got != tt.want {
+			t.Errorf("imageRepository(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
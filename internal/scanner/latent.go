@@ -0,0 +1,502 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	astypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// maxLaunchConfigurationNames is the DescribeLaunchConfigurations limit on
+// names per call.
+const maxLaunchConfigurationNames = 50
+
+// ScanLatentCapacity flags launch templates, Auto Scaling groups and open
+// Spot requests that can start GPU instances or whose user data installs an
+// AI runtime. None of them show up in DescribeInstances while they are
+// scaled to zero or waiting for capacity.
+func (s *Scanner) ScanLatentCapacity(ctx context.Context, target *Target) ([]models.Finding, error) {
+	ui.UpdateSpinner(target.Spinner, "Checking launch templates...")
+	templates, err := s.loadLaunchTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe launch templates: %w", err)
+	}
+
+	var findings []models.Finding
+	for _, lt := range templates.list {
+		findings = append(findings, lt.findings(target.Region)...)
+	}
+
+	if s.Client.AutoScaling != nil {
+		ui.UpdateSpinner(target.Spinner, "Checking Auto Scaling groups...")
+		groups, err := s.scanAutoScalingGroups(ctx, templates, target.Region)
+		if err != nil {
+			log.Printf("WARNING: Failed to describe Auto Scaling groups in %s: %v", target.Region, err)
+		}
+		findings = append(findings, groups...)
+	}
+
+	ui.UpdateSpinner(target.Spinner, "Checking Spot requests...")
+	requests, err := s.scanSpotRequests(ctx, target.Region)
+	if err != nil {
+		log.Printf("WARNING: Failed to describe Spot requests in %s: %v", target.Region, err)
+	}
+	findings = append(findings, requests...)
+
+	fleets, err := s.scanSpotFleets(ctx, templates, target.Region)
+	if err != nil {
+		log.Printf("WARNING: Failed to describe Spot Fleet requests in %s: %v", target.Region, err)
+	}
+	findings = append(findings, fleets...)
+
+	return findings, nil
+}
+
+// launchTemplate is a launch template with the data of every version.
+type launchTemplate struct {
+	ID       string
+	Name     string
+	Default  int64
+	Latest   int64
+	Versions map[int64]*types.ResponseLaunchTemplateData
+}
+
+// version resolves a version reference the way Auto Scaling and Spot Fleet
+// do: a number, $Latest, or $Default when empty.
+func (lt *launchTemplate) version(ref string) (int64, *types.ResponseLaunchTemplateData) {
+	n := lt.Default
+	switch ref {
+	case "", "$Default":
+	case "$Latest":
+		n = lt.Latest
+	default:
+		v, err := strconv.ParseInt(ref, 10, 64)
+		if err != nil {
+			return 0, nil
+		}
+		n = v
+	}
+	return n, lt.Versions[n]
+}
+
+// findings reports GPU instance types and AI bootstrapping across all
+// versions. Matches only in versions that are neither default nor latest
+// are LOW, since nothing launches them unless asked for by number.
+func (lt *launchTemplate) findings(region string) []models.Finding {
+	numbers := make([]int64, 0, len(lt.Versions))
+	for n := range lt.Versions {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+	slices.Reverse(numbers)
+
+	var gpuVersions, bootVersions []int64
+	var gpuTypes []string
+	var boot []bootstrapMatch
+	for _, n := range numbers {
+		data := lt.Versions[n]
+		if gpu := launchDataGPU(data); gpu != "" {
+			gpuVersions = append(gpuVersions, n)
+			if !slices.Contains(gpuTypes, gpu) {
+				gpuTypes = append(gpuTypes, gpu)
+			}
+		}
+		if m := userDataBootstrap(data.UserData, fmt.Sprintf("launch template %s version %d", lt.Name, n)); len(m) > 0 {
+			bootVersions = append(bootVersions, n)
+			if boot == nil {
+				boot = m
+			}
+		}
+	}
+
+	risk := func(versions []int64) models.RiskLevel {
+		if slices.Contains(versions, lt.Default) || slices.Contains(versions, lt.Latest) {
+			return models.RiskMedium
+		}
+		return models.RiskLow
+	}
+	source := fmt.Sprintf("Launch template %s (%s)", lt.Name, lt.ID)
+
+	var findings []models.Finding
+	if len(gpuVersions) > 0 {
+		findings = append(findings, latentFinding(lt.ID, lt.Name, region, risk(gpuVersions),
+			"Latent GPU Capacity", "Launch template starts GPU or ML accelerator instances",
+			fmt.Sprintf("%s %s: %s", source, lt.describeVersions(gpuVersions), strings.Join(gpuTypes, ", "))))
+	}
+	if len(bootVersions) > 0 {
+		findings = append(findings, latentFinding(lt.ID, lt.Name, region, risk(bootVersions),
+			"Latent AI Bootstrap", "Launch template user data installs or starts an AI runtime",
+			fmt.Sprintf("%s %s: %s", source, lt.describeVersions(bootVersions), bootstrapEvidence(boot))))
+	}
+	return findings
+}
+
+func (lt *launchTemplate) describeVersions(versions []int64) string {
+	s := make([]string, len(versions))
+	for i, v := range versions {
+		s[i] = strconv.FormatInt(v, 10)
+	}
+	label := "version"
+	if len(versions) > 1 {
+		label = "versions"
+	}
+	return fmt.Sprintf("%s %s (default %d, latest %d)", label, strings.Join(s, ", "), lt.Default, lt.Latest)
+}
+
+// launchTemplates indexes launch templates by ID and name, the two ways
+// Auto Scaling groups and Spot Fleets refer to them.
+type launchTemplates struct {
+	list   []*launchTemplate
+	byID   map[string]*launchTemplate
+	byName map[string]*launchTemplate
+}
+
+func (t launchTemplates) lookup(id, name *string) *launchTemplate {
+	if lt, ok := t.byID[aws.ToString(id)]; ok {
+		return lt
+	}
+	return t.byName[aws.ToString(name)]
+}
+
+// resolve describes a launch template reference for evidence and returns
+// the GPU instance type it launches, if any.
+func (t launchTemplates) resolve(id, name, version *string) (source, gpu string) {
+	ref := aws.ToString(version)
+	if ref == "" {
+		ref = "$Default"
+	}
+	lt := t.lookup(id, name)
+	if lt == nil {
+		label := aws.ToString(name)
+		if label == "" {
+			label = aws.ToString(id)
+		}
+		return fmt.Sprintf("launch template %s %s", label, ref), ""
+	}
+	n, data := lt.version(ref)
+	return fmt.Sprintf("launch template %s version %d", lt.Name, n), launchDataGPU(data)
+}
+
+func (s *Scanner) loadLaunchTemplates(ctx context.Context) (launchTemplates, error) {
+	templates := launchTemplates{
+		byID:   map[string]*launchTemplate{},
+		byName: map[string]*launchTemplate{},
+	}
+
+	paginator := ec2.NewDescribeLaunchTemplatesPaginator(s.Client.EC2, &ec2.DescribeLaunchTemplatesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return templates, err
+		}
+		for _, t := range page.LaunchTemplates {
+			lt := &launchTemplate{
+				ID:       aws.ToString(t.LaunchTemplateId),
+				Name:     aws.ToString(t.LaunchTemplateName),
+				Default:  aws.ToInt64(t.DefaultVersionNumber),
+				Latest:   aws.ToInt64(t.LatestVersionNumber),
+				Versions: map[int64]*types.ResponseLaunchTemplateData{},
+			}
+			templates.list = append(templates.list, lt)
+			templates.byID[lt.ID] = lt
+			templates.byName[lt.Name] = lt
+		}
+	}
+
+	for _, lt := range templates.list {
+		versions := ec2.NewDescribeLaunchTemplateVersionsPaginator(s.Client.EC2, &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String(lt.ID),
+		})
+		for versions.HasMorePages() {
+			page, err := versions.NextPage(ctx)
+			if err != nil {
+				log.Printf("WARNING: Failed to describe versions of launch template %s: %v", lt.Name, err)
+				break
+			}
+			for _, v := range page.LaunchTemplateVersions {
+				if v.LaunchTemplateData != nil {
+					lt.Versions[aws.ToInt64(v.VersionNumber)] = v.LaunchTemplateData
+				}
+			}
+		}
+	}
+
+	return templates, nil
+}
+
+func (s *Scanner) scanAutoScalingGroups(ctx context.Context, templates launchTemplates, region string) ([]models.Finding, error) {
+	var groups []astypes.AutoScalingGroup
+	paginator := autoscaling.NewDescribeAutoScalingGroupsPaginator(s.Client.AutoScaling, &autoscaling.DescribeAutoScalingGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, page.AutoScalingGroups...)
+	}
+
+	var names []string
+	for _, g := range groups {
+		if name := aws.ToString(g.LaunchConfigurationName); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	configs := map[string]astypes.LaunchConfiguration{}
+	for chunk := range slices.Chunk(names, maxLaunchConfigurationNames) {
+		lcs := autoscaling.NewDescribeLaunchConfigurationsPaginator(s.Client.AutoScaling, &autoscaling.DescribeLaunchConfigurationsInput{
+			LaunchConfigurationNames: chunk,
+		})
+		for lcs.HasMorePages() {
+			page, err := lcs.NextPage(ctx)
+			if err != nil {
+				log.Printf("WARNING: Failed to describe launch configurations: %v", err)
+				break
+			}
+			for _, lc := range page.LaunchConfigurations {
+				configs[aws.ToString(lc.LaunchConfigurationName)] = lc
+			}
+		}
+	}
+
+	var findings []models.Finding
+	for _, g := range groups {
+		name := aws.ToString(g.AutoScalingGroupName)
+
+		var source string
+		var gpuTypes []string
+		var boot []bootstrapMatch
+		switch {
+		case g.LaunchConfigurationName != nil:
+			lcName := aws.ToString(g.LaunchConfigurationName)
+			lc := configs[lcName]
+			source = "launch configuration " + lcName
+			if t := aws.ToString(lc.InstanceType); isAcceleratedInstanceType(t) {
+				gpuTypes = append(gpuTypes, t)
+			}
+			boot = userDataBootstrap(lc.UserData, source)
+		case g.MixedInstancesPolicy != nil && g.MixedInstancesPolicy.LaunchTemplate != nil:
+			mixed := g.MixedInstancesPolicy.LaunchTemplate
+			var gpu string
+			if spec := mixed.LaunchTemplateSpecification; spec != nil {
+				source, gpu = templates.resolve(spec.LaunchTemplateId, spec.LaunchTemplateName, spec.Version)
+			}
+			// Overrides replace the template's instance type.
+			if len(mixed.Overrides) == 0 && gpu != "" {
+				gpuTypes = append(gpuTypes, gpu)
+			}
+			for _, o := range mixed.Overrides {
+				gpu := aws.ToString(o.InstanceType)
+				if !isAcceleratedInstanceType(gpu) {
+					gpu = ""
+					if o.InstanceRequirements != nil {
+						gpu = acceleratorRequirement(o.InstanceRequirements.AcceleratorTypes)
+					}
+				}
+				if gpu != "" && !slices.Contains(gpuTypes, gpu) {
+					gpuTypes = append(gpuTypes, gpu)
+				}
+			}
+		case g.LaunchTemplate != nil:
+			var gpu string
+			source, gpu = templates.resolve(g.LaunchTemplate.LaunchTemplateId, g.LaunchTemplate.LaunchTemplateName, g.LaunchTemplate.Version)
+			if gpu != "" {
+				gpuTypes = append(gpuTypes, gpu)
+			}
+		}
+
+		if len(gpuTypes) > 0 {
+			findings = append(findings, latentFinding(name, name, region, models.RiskMedium,
+				"Latent GPU Capacity", "Auto Scaling group launches GPU or ML accelerator instances",
+				fmt.Sprintf("Auto Scaling group %s via %s: %s; desired %d (min %d, max %d)",
+					name, source, strings.Join(gpuTypes, ", "),
+					aws.ToInt32(g.DesiredCapacity), aws.ToInt32(g.MinSize), aws.ToInt32(g.MaxSize))))
+		}
+		// Template user data is reported with the template itself.
+		if len(boot) > 0 {
+			findings = append(findings, latentFinding(name, name, region, models.RiskMedium,
+				"Latent AI Bootstrap", "Launch configuration user data installs or starts an AI runtime",
+				fmt.Sprintf("Auto Scaling group %s via %s: %s", name, source, bootstrapEvidence(boot))))
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanSpotRequests(ctx context.Context, region string) ([]models.Finding, error) {
+	paginator := ec2.NewDescribeSpotInstanceRequestsPaginator(s.Client.EC2, &ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []types.Filter{
+			{Name: aws.String("state"), Values: []string{"open", "active"}},
+		},
+	})
+
+	var findings []models.Finding
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return findings, err
+		}
+		for _, r := range page.SpotInstanceRequests {
+			spec := r.LaunchSpecification
+			if spec == nil {
+				continue
+			}
+			id := aws.ToString(r.SpotInstanceRequestId)
+			name := getNameTag(r.Tags)
+
+			source := fmt.Sprintf("Spot request %s (%s, %s)", id, r.State, r.Type)
+			if r.InstanceId != nil {
+				source += ", instance " + aws.ToString(r.InstanceId)
+			}
+
+			if t := string(spec.InstanceType); isAcceleratedInstanceType(t) {
+				findings = append(findings, latentFinding(id, name, region, models.RiskMedium,
+					"Latent GPU Capacity", "Spot request for GPU or ML accelerator instances",
+					fmt.Sprintf("%s: %s", source, t)))
+			}
+			if boot := userDataBootstrap(spec.UserData, "Spot request "+id); len(boot) > 0 {
+				findings = append(findings, latentFinding(id, name, region, models.RiskMedium,
+					"Latent AI Bootstrap", "Spot request user data installs or starts an AI runtime",
+					fmt.Sprintf("%s: %s", source, bootstrapEvidence(boot))))
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// liveFleetStates are Spot Fleet request states that can still launch
+// instances. DescribeSpotFleetRequests has no state filter.
+var liveFleetStates = []types.BatchState{
+	types.BatchStateSubmitted,
+	types.BatchStateActive,
+	types.BatchStateModifying,
+}
+
+func (s *Scanner) scanSpotFleets(ctx context.Context, templates launchTemplates, region string) ([]models.Finding, error) {
+	paginator := ec2.NewDescribeSpotFleetRequestsPaginator(s.Client.EC2, &ec2.DescribeSpotFleetRequestsInput{})
+
+	var findings []models.Finding
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return findings, err
+		}
+		for _, fleet := range page.SpotFleetRequestConfigs {
+			cfg := fleet.SpotFleetRequestConfig
+			if cfg == nil || !slices.Contains(liveFleetStates, fleet.SpotFleetRequestState) {
+				continue
+			}
+			id := aws.ToString(fleet.SpotFleetRequestId)
+			name := getNameTag(fleet.Tags)
+
+			var gpuTypes []string
+			add := func(gpu string) {
+				if gpu != "" && !slices.Contains(gpuTypes, gpu) {
+					gpuTypes = append(gpuTypes, gpu)
+				}
+			}
+			var boot []bootstrapMatch
+			for _, spec := range cfg.LaunchSpecifications {
+				add(fleetSpecGPU(spec.InstanceType, spec.InstanceRequirements))
+				if boot == nil {
+					boot = userDataBootstrap(spec.UserData, "Spot Fleet request "+id)
+				}
+			}
+			for _, ltc := range cfg.LaunchTemplateConfigs {
+				if len(ltc.Overrides) > 0 {
+					for _, o := range ltc.Overrides {
+						add(fleetSpecGPU(o.InstanceType, o.InstanceRequirements))
+					}
+					continue
+				}
+				if spec := ltc.LaunchTemplateSpecification; spec != nil {
+					_, gpu := templates.resolve(spec.LaunchTemplateId, spec.LaunchTemplateName, spec.Version)
+					add(gpu)
+				}
+			}
+
+			source := fmt.Sprintf("Spot Fleet request %s (%s, target capacity %d)", id, fleet.SpotFleetRequestState, aws.ToInt32(cfg.TargetCapacity))
+			if len(gpuTypes) > 0 {
+				findings = append(findings, latentFinding(id, name, region, models.RiskMedium,
+					"Latent GPU Capacity", "Spot Fleet request for GPU or ML accelerator instances",
+					fmt.Sprintf("%s: %s", source, strings.Join(gpuTypes, ", "))))
+			}
+			if len(boot) > 0 {
+				findings = append(findings, latentFinding(id, name, region, models.RiskMedium,
+					"Latent AI Bootstrap", "Spot Fleet user data installs or starts an AI runtime",
+					fmt.Sprintf("%s: %s", source, bootstrapEvidence(boot))))
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// launchDataGPU returns the GPU instance type launch template data starts,
+// or a description of an accelerator requirement for attribute-based
+// instance selection.
+func launchDataGPU(data *types.ResponseLaunchTemplateData) string {
+	if data == nil {
+		return ""
+	}
+	return fleetSpecGPU(data.InstanceType, data.InstanceRequirements)
+}
+
+func fleetSpecGPU(instanceType types.InstanceType, req *types.InstanceRequirements) string {
+	if isAcceleratedInstanceType(string(instanceType)) {
+		return string(instanceType)
+	}
+	if req != nil {
+		return acceleratorRequirement(req.AcceleratorTypes)
+	}
+	return ""
+}
+
+// acceleratorRequirement describes attribute-based instance selection that
+// asks for GPUs or inference accelerators, or returns "".
+func acceleratorRequirement[T ~string](accelerators []T) string {
+	for _, a := range accelerators {
+		if a == "gpu" || a == "inference" {
+			return fmt.Sprintf("any %s instance", a)
+		}
+	}
+	return ""
+}
+
+// userDataBootstrap decodes user data and matches it against aiBootstraps.
+// User data that can't be decoded is logged and skipped.
+func userDataBootstrap(encoded *string, resource string) []bootstrapMatch {
+	if aws.ToString(encoded) == "" {
+		return nil
+	}
+	script, err := decodeUserData(*encoded)
+	if err != nil {
+		log.Printf("WARNING: Failed to decode user data of %s: %v", resource, err)
+		return nil
+	}
+	return matchAIBootstrap(script)
+}
+
+func latentFinding(id, name, region string, risk models.RiskLevel, service, description, evidence string) models.Finding {
+	return models.Finding{
+		InstanceID:  id,
+		Region:      region,
+		PublicIP:    "N/A",
+		PrivateIP:   "N/A",
+		NameTag:     name,
+		Risk:        risk,
+		Service:     service,
+		Description: description,
+		Evidence:    evidence,
+	}
+}
@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	astypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func templateVersion(id, name string, n int64, data types.ResponseLaunchTemplateData) types.LaunchTemplateVersion {
+	return types.LaunchTemplateVersion{
+		LaunchTemplateId:   aws.String(id),
+		LaunchTemplateName: aws.String(name),
+		VersionNumber:      aws.Int64(n),
+		LaunchTemplateData: &data,
+	}
+}
+
+func TestScanLatentCapacity(t *testing.T) {
+	ec2 := &fake.EC2{
+		LaunchTemplates: []types.LaunchTemplate{
+			{LaunchTemplateId: aws.String("lt-gpu"), LaunchTemplateName: aws.String("llm-workers"), DefaultVersionNumber: aws.Int64(2), LatestVersionNumber: aws.Int64(3)},
+			{LaunchTemplateId: aws.String("lt-old"), LaunchTemplateName: aws.String("retired"), DefaultVersionNumber: aws.Int64(2), LatestVersionNumber: aws.Int64(2)},
+			{LaunchTemplateId: aws.String("lt-web"), LaunchTemplateName: aws.String("web"), DefaultVersionNumber: aws.Int64(1), LatestVersionNumber: aws.Int64(1)},
+		},
+		LaunchTemplateVersions: []types.LaunchTemplateVersion{
+			templateVersion("lt-gpu", "llm-workers", 1, types.ResponseLaunchTemplateData{InstanceType: types.InstanceTypeT3Large}),
+			templateVersion("lt-gpu", "llm-workers", 2, types.ResponseLaunchTemplateData{
+				InstanceType: types.InstanceTypeG5Xlarge,
+				UserData:     gzipUserData("#!/bin/bash\n# install ollama\ncurl -fsSL https://ollama.com/install.sh | sh\nollama pull llama3\n"),
+			}),
+			templateVersion("lt-gpu", "llm-workers", 3, types.ResponseLaunchTemplateData{
+				InstanceRequirements: &types.InstanceRequirements{AcceleratorTypes: []types.AcceleratorType{types.AcceleratorTypeGpu}},
+			}),
+			templateVersion("lt-old", "retired", 1, types.ResponseLaunchTemplateData{InstanceType: types.InstanceTypeP3dn24xlarge}),
+			templateVersion("lt-old", "retired", 2, types.ResponseLaunchTemplateData{InstanceType: types.InstanceTypeM5Large}),
+			templateVersion("lt-web", "web", 1, types.ResponseLaunchTemplateData{
+				InstanceType: types.InstanceTypeT3Micro,
+				UserData:     userData("#!/bin/bash\nyum install -y nginx\n"),
+			}),
+		},
+		SpotInstanceRequests: []types.SpotInstanceRequest{
+			{
+				SpotInstanceRequestId: aws.String("sir-open"),
+				State:                 types.SpotInstanceStateOpen,
+				Type:                  types.SpotInstanceTypePersistent,
+				LaunchSpecification: &types.LaunchSpecification{
+					InstanceType: types.InstanceTypeP4d24xlarge,
+					UserData:     userData("pip install vllm==0.6.3\npython -m vllm.entrypoints.openai.api_server --model meta-llama/Llama-3.1-8B\n"),
+				},
+			},
+			{
+				SpotInstanceRequestId: aws.String("sir-done"),
+				State:                 types.SpotInstanceStateCancelled,
+				LaunchSpecification:   &types.LaunchSpecification{InstanceType: types.InstanceTypeG5Xlarge},
+			},
+		},
+		SpotFleetRequests: []types.SpotFleetRequestConfig{
+			{
+				SpotFleetRequestId:    aws.String("sfr-train"),
+				SpotFleetRequestState: types.BatchStateActive,
+				SpotFleetRequestConfig: &types.SpotFleetRequestConfigData{
+					TargetCapacity: aws.Int32(4),
+					LaunchTemplateConfigs: []types.LaunchTemplateConfig{{
+						LaunchTemplateSpecification: &types.FleetLaunchTemplateSpecification{LaunchTemplateName: aws.String("web"), Version: aws.String("1")},
+						Overrides: []types.LaunchTemplateOverrides{
+							{InstanceType: types.InstanceTypeTrn132xlarge},
+							{InstanceType: types.InstanceTypeM5Large},
+						},
+					}},
+				},
+			},
+			{
+				SpotFleetRequestId:    aws.String("sfr-gone"),
+				SpotFleetRequestState: types.BatchStateCancelled,
+				SpotFleetRequestConfig: &types.SpotFleetRequestConfigData{
+					LaunchSpecifications: []types.SpotFleetLaunchSpecification{{InstanceType: types.InstanceTypeP548xlarge}},
+				},
+			},
+		},
+	}
+	asg := &fake.AutoScaling{
+		Groups: []astypes.AutoScalingGroup{
+			{
+				AutoScalingGroupName: aws.String("inference"),
+				LaunchTemplate:       &astypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-gpu")},
+				DesiredCapacity:      aws.Int32(0), MinSize: aws.Int32(0), MaxSize: aws.Int32(8),
+			},
+			{
+				AutoScalingGroupName: aws.String("mixed"),
+				MixedInstancesPolicy: &astypes.MixedInstancesPolicy{LaunchTemplate: &astypes.LaunchTemplate{
+					LaunchTemplateSpecification: &astypes.LaunchTemplateSpecification{LaunchTemplateName: aws.String("web"), Version: aws.String("$Latest")},
+					Overrides:                   []astypes.LaunchTemplateOverrides{{InstanceType: aws.String("m5.large")}, {InstanceType: aws.String("g6.xlarge")}},
+				}},
+				DesiredCapacity: aws.Int32(1), MinSize: aws.Int32(0), MaxSize: aws.Int32(2),
+			},
+			{
+				AutoScalingGroupName:    aws.String("legacy"),
+				LaunchConfigurationName: aws.String("legacy-lc"),
+				DesiredCapacity:         aws.Int32(0), MinSize: aws.Int32(0), MaxSize: aws.Int32(1),
+			},
+			{
+				AutoScalingGroupName: aws.String("web"),
+				LaunchTemplate:       &astypes.LaunchTemplateSpecification{LaunchTemplateName: aws.String("web"), Version: aws.String("$Latest")},
+			},
+		},
+		LaunchConfigurations: []astypes.LaunchConfiguration{{
+			LaunchConfigurationName: aws.String("legacy-lc"),
+			InstanceType:            aws.String("t3.large"),
+			UserData:                userData("docker run -d --gpus all -p 8000:8000 vllm/vllm-openai:latest\n"),
+		}},
+	}
+
+	scn := newTestScanner(ec2, nil, nil)
+	scn.Client.AutoScaling = asg
+
+	findings, err := scn.ScanLatentCapacity(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanLatentCapacity: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.Finding{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f
+	}
+
+	want := map[key]models.RiskLevel{
+		{"lt-gpu", "Latent GPU Capacity"}:    models.RiskMedium,
+		{"lt-gpu", "Latent AI Bootstrap"}:    models.RiskMedium,
+		{"lt-old", "Latent GPU Capacity"}:    models.RiskLow,
+		{"inference", "Latent GPU Capacity"}: models.RiskMedium,
+		{"mixed", "Latent GPU Capacity"}:     models.RiskMedium,
+		{"legacy", "Latent AI Bootstrap"}:    models.RiskMedium,
+		{"sir-open", "Latent GPU Capacity"}:  models.RiskMedium,
+		{"sir-open", "Latent AI Bootstrap"}:  models.RiskMedium,
+		{"sfr-train", "Latent GPU Capacity"}: models.RiskMedium,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k].Risk != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k].Risk, risk)
+		}
+	}
+
+	checks := map[key]string{
+		{"lt-gpu", "Latent GPU Capacity"}:    "versions 3, 2 (default 2, latest 3): any gpu instance, g5.xlarge",
+		{"lt-gpu", "Latent AI Bootstrap"}:    "version 2 (default 2, latest 3): Ollama (curl -fsSL https://ollama.com/install.sh | sh)",
+		{"inference", "Latent GPU Capacity"}: "via launch template llm-workers version 2: g5.xlarge; desired 0 (min 0, max 8)",
+		{"mixed", "Latent GPU Capacity"}:     "launch template web version 1: g6.xlarge;",
+		{"legacy", "Latent AI Bootstrap"}:    "via launch configuration legacy-lc: vLLM (docker run",
+		{"sir-open", "Latent AI Bootstrap"}:  "Spot request sir-open (open, persistent): vLLM (pip install vllm==0.6.3)",
+		{"sfr-train", "Latent GPU Capacity"}: "(active, target capacity 4): trn1.32xlarge",
+	}
+	for k, want := range checks {
+		if ev := got[k].Evidence; !strings.Contains(ev, want) {
+			t.Errorf("%s / %s: evidence = %q, want it to contain %q", k.resource, k.service, ev, want)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// network is the region's VPC layout, used to decide whether an open
+// security group is actually reachable from the internet.
+type network struct {
+	subnets map[string]types.Subnet
+	// routeTables holds explicit subnet associations, mainRouteTables the
+	// VPC main tables that every other subnet falls back to.
+	routeTables     map[string]types.RouteTable
+	mainRouteTables map[string]types.RouteTable
+	nacls           map[string]types.NetworkAcl
+}
+
+// loadNetwork describes every subnet, route table and network ACL in the
+// region.
+func (s *Scanner) loadNetwork(ctx context.Context) (*network, error) {
+	n := &network{
+		subnets:         map[string]types.Subnet{},
+		routeTables:     map[string]types.RouteTable{},
+		mainRouteTables: map[string]types.RouteTable{},
+		nacls:           map[string]types.NetworkAcl{},
+	}
+
+	subnets := ec2.NewDescribeSubnetsPaginator(s.Client.EC2, &ec2.DescribeSubnetsInput{})
+	for subnets.HasMorePages() {
+		page, err := subnets.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("describe subnets: %w", err)
+		}
+		for _, sn := range page.Subnets {
+			n.subnets[aws.ToString(sn.SubnetId)] = sn
+		}
+	}
+
+	tables := ec2.NewDescribeRouteTablesPaginator(s.Client.EC2, &ec2.DescribeRouteTablesInput{})
+	for tables.HasMorePages() {
+		page, err := tables.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("describe route tables: %w", err)
+		}
+		for _, rt := range page.RouteTables {
+			for _, a := range rt.Associations {
+				if aws.ToBool(a.Main) {
+					n.mainRouteTables[aws.ToString(rt.VpcId)] = rt
+				} else if a.SubnetId != nil {
+					n.routeTables[*a.SubnetId] = rt
+				}
+			}
+		}
+	}
+
+	acls := ec2.NewDescribeNetworkAclsPaginator(s.Client.EC2, &ec2.DescribeNetworkAclsInput{})
+	for acls.HasMorePages() {
+		page, err := acls.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("describe network ACLs: %w", err)
+		}
+		for _, acl := range page.NetworkAcls {
+			for _, a := range acl.Associations {
+				n.nacls[aws.ToString(a.SubnetId)] = acl
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// reachability is the verdict for one exposed port on one instance.
+type reachability struct {
+	// Known is false when the network layout couldn't be resolved, in which
+	// case the finding keeps its catalogue risk.
+	Known     bool
+	Reachable bool
+	Reason    string
+}
+
+// rank orders verdicts from definitely unreachable (0) to reachable (2).
+func (r reachability) rank() int {
+	switch {
+	case r.Reachable:
+		return 2
+	case !r.Known:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// applyReachability caps findings with no internet path at MEDIUM: the port
+// is still open to anything inside the VPC, peered networks and VPNs.
+func applyReachability(f *models.Finding, r reachability) {
+	switch {
+	case !r.Known:
+	case r.Reachable:
+		f.Evidence += "; internet reachable: " + r.Reason
+	default:
+		f.Risk = f.Risk.AtMost(models.RiskMedium)
+		f.Description += " (no internet path)"
+		f.Evidence += "; not internet reachable: " + r.Reason
+	}
+}
+
+// eni is the part of a network interface that matters for reachability.
+type eni struct {
+	SubnetID string
+	PublicIP string
+	IPv6     bool
+	Groups   []string
+}
+
+// instanceENIs returns the instance's network interfaces, or a single
+// synthetic one built from the instance fields when none are listed.
+func instanceENIs(instance types.Instance) []eni {
+	if len(instance.NetworkInterfaces) == 0 {
+		e := eni{
+			SubnetID: aws.ToString(instance.SubnetId),
+			PublicIP: aws.ToString(instance.PublicIpAddress),
+			IPv6:     aws.ToString(instance.Ipv6Address) != "",
+		}
+		for _, g := range instance.SecurityGroups {
+			e.Groups = append(e.Groups, aws.ToString(g.GroupId))
+		}
+		return []eni{e}
+	}
+
+	var enis []eni
+	for _, ni := range instance.NetworkInterfaces {
+		e := eni{
+			SubnetID: aws.ToString(ni.SubnetId),
+			IPv6:     len(ni.Ipv6Addresses) > 0,
+		}
+		if ni.Association != nil {
+			e.PublicIP = aws.ToString(ni.Association.PublicIp)
+		}
+		for _, g := range ni.Groups {
+			e.Groups = append(e.Groups, aws.ToString(g.GroupId))
+		}
+		enis = append(enis, e)
+	}
+	return enis
+}
+
+// reachable decides whether port is reachable from the internet through any
+// interface that carries groupID. v4 and v6 say which address families the
+// security group rule opens. n may be nil when the layout couldn't be
+// loaded; only the public IP check is applied then.
+func (n *network) reachable(instance types.Instance, groupID string, port int32, v4, v6 bool) reachability {
+	var blocked []string
+	unknown := false
+
+	for _, e := range instanceENIs(instance) {
+		if !slices.Contains(e.Groups, groupID) {
+			continue
+		}
+
+		var families []bool // true for IPv6
+		if v4 && e.PublicIP != "" {
+			families = append(families, false)
+		}
+		if v6 && e.IPv6 {
+			families = append(families, true)
+		}
+		if len(families) == 0 {
+			blocked = append(blocked, "no public IP address")
+			continue
+		}
+
+		subnet, ok := n.subnet(e.SubnetID)
+		if !ok {
+			unknown = true
+			continue
+		}
+
+		for _, ipv6 := range families {
+			igw, nat := n.internetRoute(subnet, ipv6)
+			if igw == "" {
+				reason := fmt.Sprintf("%s has no route to an internet gateway", e.SubnetID)
+				if nat != "" {
+					reason += fmt.Sprintf(" (outbound only via %s)", nat)
+				}
+				blocked = append(blocked, reason)
+				continue
+			}
+
+			acl, ok := n.nacls[e.SubnetID]
+			if ok && !naclAllows(acl, port, ipv6) {
+				blocked = append(blocked, fmt.Sprintf("network ACL %s denies port %d", aws.ToString(acl.NetworkAclId), port))
+				continue
+			}
+
+			addr := e.PublicIP
+			if ipv6 {
+				addr = "IPv6"
+			}
+			return reachability{Known: true, Reachable: true, Reason: fmt.Sprintf("%s via %s", addr, igw)}
+		}
+	}
+
+	if unknown || len(blocked) == 0 {
+		return reachability{}
+	}
+	return reachability{Known: true, Reason: blocked[0]}
+}
+
+func (n *network) subnet(id string) (types.Subnet, bool) {
+	if n == nil || id == "" {
+		return types.Subnet{}, false
+	}
+	sn, ok := n.subnets[id]
+	return sn, ok
+}
+
+// internetRoute returns the internet gateway the subnet routes to for the
+// address family, or the NAT gateway its default route uses when it has no
+// internet gateway route.
+func (n *network) internetRoute(subnet types.Subnet, ipv6 bool) (igw, nat string) {
+	rt, ok := n.routeTables[aws.ToString(subnet.SubnetId)]
+	if !ok {
+		rt, ok = n.mainRouteTables[aws.ToString(subnet.VpcId)]
+	}
+	if !ok {
+		return "", ""
+	}
+
+	for _, r := range rt.Routes {
+		if r.State == types.RouteStateBlackhole {
+			continue
+		}
+		dest := aws.ToString(r.DestinationCidrBlock)
+		if ipv6 {
+			dest = aws.ToString(r.DestinationIpv6CidrBlock)
+		}
+		if dest == "" {
+			continue
+		}
+
+		gw := aws.ToString(r.GatewayId)
+		if strings.HasPrefix(gw, "igw-") {
+			return gw, ""
+		}
+		if r.NatGatewayId != nil && (dest == "0.0.0.0/0" || dest == "::/0") {
+			nat = *r.NatGatewayId
+		}
+	}
+	return "", nat
+}
+
+// naclAllows evaluates the ACL's inbound rules in order for a TCP packet to
+// port from an arbitrary internet address. An allow rule for any public
+// range counts, since some part of the internet gets through.
+func naclAllows(acl types.NetworkAcl, port int32, ipv6 bool) bool {
+	entries := slices.Clone(acl.Entries)
+	slices.SortFunc(entries, func(a, b types.NetworkAclEntry) int {
+		return int(aws.ToInt32(a.RuleNumber) - aws.ToInt32(b.RuleNumber))
+	})
+
+	for _, e := range entries {
+		if aws.ToBool(e.Egress) {
+			continue
+		}
+
+		cidr := aws.ToString(e.CidrBlock)
+		if ipv6 {
+			cidr = aws.ToString(e.Ipv6CidrBlock)
+		}
+		if cidr == "" {
+			continue
+		}
+
+		switch aws.ToString(e.Protocol) {
+		case "-1":
+		case "6":
+			if e.PortRange != nil && (port < aws.ToInt32(e.PortRange.From) || port > aws.ToInt32(e.PortRange.To)) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if cidr == "0.0.0.0/0" || cidr == "::/0" {
+			return e.RuleAction == types.RuleActionAllow
+		}
+		if e.RuleAction == types.RuleActionAllow && isPublicPrefix(cidr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPublicPrefix(cidr string) bool {
+	p, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return false
+	}
+	a := p.Addr()
+	return !a.IsPrivate() && !a.IsLoopback() && !a.IsLinkLocalUnicast()
+}
@@ -0,0 +1,179 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func route(dest, gateway string) types.Route {
+	r := types.Route{DestinationCidrBlock: aws.String(dest), State: types.RouteStateActive}
+	if strings.HasPrefix(gateway, "nat-") {
+		r.NatGatewayId = aws.String(gateway)
+	} else {
+		r.GatewayId = aws.String(gateway)
+	}
+	return r
+}
+
+func naclEntry(rule int32, action types.RuleAction, from, to int32) types.NetworkAclEntry {
+	return types.NetworkAclEntry{
+		RuleNumber: aws.Int32(rule),
+		RuleAction: action,
+		Protocol:   aws.String("6"),
+		CidrBlock:  aws.String("0.0.0.0/0"),
+		PortRange:  &types.PortRange{From: aws.Int32(from), To: aws.Int32(to)},
+		Egress:     aws.Bool(false),
+	}
+}
+
+func TestScanReachability(t *testing.T) {
+	allowAll := []types.NetworkAclEntry{
+		{RuleNumber: aws.Int32(100), RuleAction: types.RuleActionAllow, Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0"), Egress: aws.Bool(false)},
+		{RuleNumber: aws.Int32(32767), RuleAction: types.RuleActionDeny, Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0"), Egress: aws.Bool(false)},
+	}
+
+	tests := []struct {
+		name     string
+		publicIP bool
+		routes   []types.Route
+		acl      []types.NetworkAclEntry
+		want     models.RiskLevel
+		evidence string
+	}{
+		{
+			name:     "public subnet",
+			publicIP: true,
+			routes:   []types.Route{route("10.0.0.0/16", "local"), route("0.0.0.0/0", "igw-1")},
+			acl:      allowAll,
+			want:     models.RiskCritical,
+			evidence: "internet reachable: 203.0.113.10 via igw-1",
+		},
+		{
+			name:     "private subnet behind nat",
+			publicIP: true,
+			routes:   []types.Route{route("10.0.0.0/16", "local"), route("0.0.0.0/0", "nat-1")},
+			acl:      allowAll,
+			want:     models.RiskMedium,
+			evidence: "subnet-1 has no route to an internet gateway (outbound only via nat-1)",
+		},
+		{
+			name:     "no public ip",
+			routes:   []types.Route{route("0.0.0.0/0", "igw-1")},
+			acl:      allowAll,
+			want:     models.RiskMedium,
+			evidence: "no public IP address",
+		},
+		{
+			name:     "nacl denies port",
+			publicIP: true,
+			routes:   []types.Route{route("0.0.0.0/0", "igw-1")},
+			acl:      append([]types.NetworkAclEntry{naclEntry(50, types.RuleActionDeny, 11000, 12000)}, allowAll...),
+			want:     models.RiskMedium,
+			evidence: "network ACL acl-1 denies port 11434",
+		},
+		{
+			name:     "nacl allows port before later deny",
+			publicIP: true,
+			routes:   []types.Route{route("0.0.0.0/0", "igw-1")},
+			acl: []types.NetworkAclEntry{
+				naclEntry(10, types.RuleActionAllow, 11434, 11434),
+				naclEntry(20, types.RuleActionDeny, 0, 65535),
+			},
+			want: models.RiskCritical,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := testInstance("i-1", "sg-1")
+			inst.Ipv6Address = nil
+			inst.SubnetId = aws.String("subnet-1")
+			if !tt.publicIP {
+				inst.PublicIpAddress = nil
+			}
+
+			ec2 := &fake.EC2{
+				Instances:      []types.Instance{inst},
+				SecurityGroups: []types.SecurityGroup{{GroupId: aws.String("sg-1"), IpPermissions: []types.IpPermission{tcpRule(11434, 11434, "0.0.0.0/0")}}},
+				Subnets:        []types.Subnet{{SubnetId: aws.String("subnet-1"), VpcId: aws.String("vpc-1")}},
+				RouteTables: []types.RouteTable{{
+					VpcId:        aws.String("vpc-1"),
+					Associations: []types.RouteTableAssociation{{Main: aws.Bool(true)}},
+					Routes:       tt.routes,
+				}},
+				NetworkAcls: []types.NetworkAcl{{
+					NetworkAclId: aws.String("acl-1"),
+					Associations: []types.NetworkAclAssociation{{SubnetId: aws.String("subnet-1")}},
+					Entries:      tt.acl,
+				}},
+			}
+			scn := newTestScanner(ec2, nil, nil)
+
+			findings, err := scn.Scan(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+
+			f, ok := findingsByService(findings)["Ollama API"]
+			if !ok {
+				t.Fatalf("missing Ollama finding in %v", findings)
+			}
+			if f.Risk != tt.want {
+				t.Errorf("risk = %s, want %s", f.Risk, tt.want)
+			}
+			if !strings.Contains(f.Evidence, tt.evidence) {
+				t.Errorf("evidence = %q, want it to contain %q", f.Evidence, tt.evidence)
+			}
+		})
+	}
+}
+
+func TestReachabilityPrefersWidestGroup(t *testing.T) {
+	inst := testInstance("i-1", "sg-private", "sg-public")
+	inst.Ipv6Address = nil
+	inst.SubnetId = aws.String("subnet-1")
+	inst.NetworkInterfaces = []types.InstanceNetworkInterface{
+		{
+			SubnetId: aws.String("subnet-1"),
+			Groups:   []types.GroupIdentifier{{GroupId: aws.String("sg-private")}},
+		},
+		{
+			SubnetId:    aws.String("subnet-1"),
+			Association: &types.InstanceNetworkInterfaceAssociation{PublicIp: aws.String("203.0.113.10")},
+			Groups:      []types.GroupIdentifier{{GroupId: aws.String("sg-public")}},
+		},
+	}
+
+	rule := []types.IpPermission{tcpRule(8888, 8888, "0.0.0.0/0")}
+	ec2 := &fake.EC2{
+		Instances: []types.Instance{inst},
+		SecurityGroups: []types.SecurityGroup{
+			{GroupId: aws.String("sg-private"), IpPermissions: rule},
+			{GroupId: aws.String("sg-public"), IpPermissions: rule},
+		},
+		Subnets: []types.Subnet{{SubnetId: aws.String("subnet-1"), VpcId: aws.String("vpc-1")}},
+		RouteTables: []types.RouteTable{{
+			VpcId:        aws.String("vpc-1"),
+			Associations: []types.RouteTableAssociation{{SubnetId: aws.String("subnet-1")}},
+			Routes:       []types.Route{route("0.0.0.0/0", "igw-1")},
+		}},
+	}
+	scn := newTestScanner(ec2, nil, nil)
+
+	findings, err := scn.Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1: %v", len(findings), findings)
+	}
+	if findings[0].Risk != models.RiskCritical || !strings.Contains(findings[0].Evidence, "sg-public") {
+		t.Errorf("unexpected finding %+v", findings[0])
+	}
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+)
+
+// AIPort describes a TCP port commonly used by an AI/ML service and the
+// risk of exposing it to the internet.
+type AIPort struct {
+	Port        int32
+	Name        string
+	Risk        models.RiskLevel
+	Description string
+}
+
+// DefaultAIPorts is the built-in port catalogue. Entries can be overridden
+// by port number with MergePorts.
+var DefaultAIPorts = []AIPort{
+	{Port: 5000, Name: "MLflow / Flask", Risk: models.RiskHigh},
+	{Port: 7860, Name: "Gradio (HuggingFace)", Risk: models.RiskHigh},
+	{Port: 8000, Name: "vLLM / FastChat", Risk: models.RiskHigh},
+	{Port: 8265, Name: "Ray Dashboard", Risk: models.RiskCritical},
+	{Port: 8501, Name: "Streamlit App", Risk: models.RiskHigh},
+	{Port: 8888, Name: "Jupyter Notebook", Risk: models.RiskCritical},
+	{Port: 11434, Name: "Ollama API", Risk: models.RiskCritical},
+}
+
+// MergePorts returns the defaults with overrides applied. An override for a
+// port already in defaults replaces it, keeping the default name and risk
+// when the override leaves them empty. A new port without a name is called
+// "port <n>". The result is sorted by port.
+func MergePorts(defaults, overrides []AIPort) []AIPort {
+	byPort := make(map[int32]AIPort, len(defaults)+len(overrides))
+	for _, p := range defaults {
+		byPort[p.Port] = p
+	}
+
+	for _, o := range overrides {
+		if existing, ok := byPort[o.Port]; ok {
+			if o.Name == "" {
+				o.Name = existing.Name
+			}
+			if o.Risk == "" {
+				o.Risk = existing.Risk
+			}
+			if o.Description == "" {
+				o.Description = existing.Description
+			}
+		}
+		if o.Name == "" {
+			o.Name = fmt.Sprintf("port %d", o.Port)
+			if o.Description == "" {
+				o.Description = fmt.Sprintf("Exposed custom port %d", o.Port)
+			}
+		}
+		if o.Risk == "" {
+			o.Risk = models.RiskHigh
+		}
+		byPort[o.Port] = o
+	}
+
+	merged := make([]AIPort, 0, len(byPort))
+	for _, p := range byPort {
+		merged = append(merged, p)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Port < merged[j].Port
+	})
+
+	return merged
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/probe"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/pterm/pterm"
+)
+
+// probeWorkers bounds concurrent HTTP probes.
+const probeWorkers = 16
+
+// probeExposures fingerprints the port of each candidate finding that has a
+// public IP, confirms or downgrades the finding in place, and returns extra
+// findings for APIs that answered without credentials.
+func (s *Scanner) probeExposures(ctx context.Context, findings []models.Finding, candidates []int, spinner *pterm.SpinnerPrinter) []models.Finding {
+	var targets []int
+	for _, i := range candidates {
+		if f := findings[i]; f.PublicIP != "" && f.PublicIP != "N/A" && f.Port != 0 {
+			targets = append(targets, i)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// extra is indexed like findings so the output order is stable.
+	extra := make([][]models.Finding, len(findings))
+
+	var mu sync.Mutex
+	done := 0
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(probeWorkers, len(targets)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f := &findings[i]
+				result, err := s.Prober.Fingerprint(ctx, f.PublicIP, f.Port)
+				if err == nil {
+					_ = s.Prober.CheckAuth(ctx, f.PublicIP, f.Port, result)
+					applyProbeResult(f, result)
+					extra[i] = exposureFindings(*f, result)
+				}
+
+				mu.Lock()
+				done++
+				ui.UpdateSpinner(spinner, fmt.Sprintf("Probing exposed endpoints (%d/%d)...", done, len(targets)))
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var out []models.Finding
+	for _, e := range extra {
+		out = append(out, e...)
+	}
+	return out
+}
+
+// applyProbeResult keeps the finding's risk when an AI service answered
+// anonymously, and caps it at MEDIUM when the service demanded credentials,
+// something unrecognised answered or nothing answered at all. Silence is
+// weak evidence, since egress filtering on the scanner's side looks the
+// same, and a probe cut short by its context says nothing, so that leaves
+// the risk alone.
+func applyProbeResult(f *models.Finding, result *probe.Result) {
+	switch {
+	case result.Confirmed():
+		f.Description = fmt.Sprintf("Confirmed %s on port %d", result.Service, f.Port)
+		f.Evidence += "; probe: " + result.Detail
+		if len(result.Models) > 0 {
+			f.Evidence += "; models: " + strings.Join(result.Models[:min(5, len(result.Models))], ", ")
+		}
+		if result.Auth == probe.AuthRequired {
+			f.Risk = f.Risk.AtMost(models.RiskMedium)
+			f.Evidence += "; authentication required"
+		}
+	case result.Reachable:
+		f.Risk = f.Risk.AtMost(models.RiskMedium)
+		f.Description += " (unrecognised service)"
+		f.Evidence += fmt.Sprintf("; probe: %s answered but no AI fingerprint matched", result.Scheme)
+	case errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded):
+		f.Evidence += "; probe: interrupted before a response"
+	default:
+		f.Risk = f.Risk.AtMost(models.RiskMedium)
+		f.Description += " (no response)"
+		f.Evidence += "; probe: no HTTP response from public IP"
+	}
+}
+
+// exposureFindings turns each anonymous API into its own finding, separate
+// from the port-open finding it was found through.
+func exposureFindings(port models.Finding, result *probe.Result) []models.Finding {
+	var findings []models.Finding
+	for _, e := range result.Exposures {
+		f := port
+		f.Risk = impactRisk(e.Impact)
+		f.Service = e.Title
+		f.Description = e.Description
+		f.Evidence = fmt.Sprintf("%s at %s://%s:%d", e.Evidence, result.Scheme, port.PublicIP, port.Port)
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func impactRisk(i probe.Impact) models.RiskLevel {
+	switch i {
+	case probe.ImpactExec:
+		return models.RiskCritical
+	case probe.ImpactAbuse:
+		return models.RiskHigh
+	default:
+		return models.RiskMedium
+	}
+}
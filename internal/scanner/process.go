@@ -2,24 +2,78 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/K0NGR3SS/ghostweights/internal/models"
 	"github.com/K0NGR3SS/ghostweights/internal/ui"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/pterm/pterm"
 )
 
+// apiKeyPattern matches environment entries that hold LLM provider keys. It
+// is an extended regular expression so the deep scan script can grep with
+// it; apiKeyEnv is the same pattern for entries read through the API.
+const apiKeyPattern = "api_key|openai|anthropic|huggingface|together"
+
+var apiKeyEnv = regexp.MustCompile("(?i)" + apiKeyPattern)
+
+// minAPIKeyLength is shorter than any provider's keys but longer than
+// placeholders like "changeme".
+const minAPIKeyLength = 16
+
+// secretName narrows apiKeyEnv to names that hold a secret rather than,
+// say, OPENAI_BASE_URL.
+var secretName = regexp.MustCompile(`(?i)key|token|secret`)
+
+// providerKey matches LLM provider key formats wherever they appear:
+// Anthropic, OpenAI, Hugging Face, Groq and Replicate.
+var providerKey = regexp.MustCompile(`\b(sk-ant-[A-Za-z0-9_-]{20,}|sk-(?:proj-)?[A-Za-z0-9_-]{20,}|hf_[A-Za-z0-9]{30,}|gsk_[A-Za-z0-9]{40,}|r8_[A-Za-z0-9]{30,})`)
+
+// isAPIKeyEntry reports whether an environment variable read through the
+// API holds an LLM provider key: either its name is a provider key name and
+// its value looks like a literal secret, or its value is shaped like a
+// provider key. Provider names alone, as in OPENAI_BASE_URL or
+// LLM_PROVIDER=anthropic, don't count, and neither do references such as
+// ${OPENAI_API_KEY} or placeholders too short to be a real key.
+func isAPIKeyEntry(name, value string) bool {
+	if providerKey.MatchString(value) {
+		return true
+	}
+	return apiKeyEnv.MatchString(name) && secretName.MatchString(name) &&
+		len(value) >= minAPIKeyLength && !strings.ContainsAny(value[:1], "$<{%")
+}
+
 var suspiciousProcesses = []string{
 	"ollama", "streamlit", "vllm", "text-generation",
 	"ray", "jupyter", "python", "uvicorn", "gunicorn",
 	"llama-cpp", "koboldcpp", "oobabooga", "localai",
 }
 
+const (
+	// ssmMaxTargets is the SendCommand limit on instance IDs per call.
+	ssmMaxTargets = 50
+	// ssmPollWorkers bounds concurrent GetCommandInvocation pollers.
+	ssmPollWorkers = 10
+	// ssmMaxRetries caps retries of a throttled SendCommand.
+	ssmMaxRetries = 5
+
+	ssmInvocationTimeout = 90 * time.Second
+	ssmMaxPollInterval   = 10 * time.Second
+)
+
+// ssmPollInterval is the initial delay between invocation polls. It is a
+// variable so tests can shorten it.
+var ssmPollInterval = 1 * time.Second
+
 func (s *Scanner) DeepScan(ctx context.Context, instanceIDs []string, spinner *pterm.SpinnerPrinter) ([]models.Finding, error) {
 	var findings []models.Finding
 	if len(instanceIDs) == 0 {
@@ -76,7 +130,7 @@ if command -v jupyter &> /dev/null; then
 fi
 
 # 6. Look for API keys in environment
-env | grep -iE 'api_key|openai|anthropic|huggingface|together' | while read line; do
+env | grep -iE '` + apiKeyPattern + `' | while read line; do
 	echo "API_KEY|$line"
 done
 
@@ -89,230 +143,362 @@ for dir in /opt/models /home/*/models ~/.cache/huggingface ~/.cache/ollama; do
 done
 `
 
-	out, err := s.Client.SSM.SendCommand(ctx, &ssm.SendCommandInput{
-		InstanceIds:  instanceIDs,
-		DocumentName: aws.String("AWS-RunShellScript"),
-		Parameters: map[string][]string{
-			"commands": {cmdScript},
-		},
-		TimeoutSeconds: aws.Int32(60),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to send SSM command: %w", err)
+	targets, unmanaged := s.managedInstances(ctx, instanceIDs)
+	for _, instanceID := range unmanaged {
+		findings = append(findings, models.Finding{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        models.RiskLow,
+			Service:     "SSM Agent",
+			Description: "Deep scan skipped - instance not managed by SSM or offline",
+			Evidence:    "Not reported Online by ssm:DescribeInstanceInformation",
+		})
+	}
+	if len(targets) == 0 {
+		return findings, nil
 	}
 
-	if out.Command == nil || out.Command.CommandId == nil {
-		return nil, fmt.Errorf("ssm SendCommand returned empty command id")
+	invocations, sendErr := s.sendDeepScanCommands(ctx, targets, cmdScript)
+	if len(invocations) == 0 && sendErr != nil {
+		return findings, sendErr
+	}
+	if sendErr != nil {
+		pterm.Warning.Printf("Some SSM batches failed to send: %v\n", sendErr)
 	}
-	commandID := *out.Command.CommandId
 
-	successCount := 0
-	failCount := 0
+	var (
+		mu           sync.Mutex
+		results      = make([][]models.Finding, len(invocations))
+		done         int
+		successCount int
+		failCount    int
+	)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(ssmPollWorkers, len(invocations)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				inv := invocations[i]
+				instanceFindings, ok := s.collectDeepScan(ctx, inv)
+
+				mu.Lock()
+				results[i] = instanceFindings
+				done++
+				if ok {
+					successCount++
+				} else {
+					failCount++
+				}
+				ui.UpdateSpinner(spinner, fmt.Sprintf("Deep Scanning (%d/%d instances)...", done, len(invocations)))
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range invocations {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, r := range results {
+		findings = append(findings, r...)
+	}
 
-	for i, instanceID := range instanceIDs {
-		ui.UpdateSpinner(spinner, fmt.Sprintf("Deep Scanning %s (%d/%d)...", instanceID, i+1, len(instanceIDs)))
+	pterm.Info.Printf("SSM Deep Scan: %d succeeded, %d failed\n", successCount, failCount)
 
-		stdout, status, err := s.waitCommandOutput(ctx, commandID, instanceID, 30*time.Second)
+	return findings, nil
+}
+
+// ssmInvocation identifies one instance's share of a SendCommand call.
+type ssmInvocation struct {
+	commandID  string
+	instanceID string
+}
+
+// managedInstances splits instanceIDs into those SSM reports as managed and
+// online, and the rest. If the lookup itself fails, every instance is
+// treated as a target so missing ssm:DescribeInstanceInformation permission
+// doesn't disable the deep scan.
+func (s *Scanner) managedInstances(ctx context.Context, instanceIDs []string) (targets, unmanaged []string) {
+	online := map[string]bool{}
+
+	paginator := ssm.NewDescribeInstanceInformationPaginator(s.Client.SSM, &ssm.DescribeInstanceInformationInput{
+		Filters: []types.InstanceInformationStringFilter{
+			{Key: aws.String("PingStatus"), Values: []string{string(types.PingStatusOnline)}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			failCount++
-			pterm.Warning.Printf("SSM failed on %s: %v\n", instanceID, err)
-			findings = append(findings, models.Finding{
-				InstanceID:  instanceID,
-				Region:      s.Client.Region,
-				Risk:        models.RiskLow,
-				Service:     "SSM Agent",
-				Description: "Deep scan failed - SSM may not be installed",
-				Evidence:    fmt.Sprintf("Error: %v", err),
-			})
-			continue
+			pterm.Warning.Printf("Could not list SSM managed instances, sending to all: %v\n", err)
+			return instanceIDs, nil
 		}
-		
-		if status != types.CommandInvocationStatusSuccess {
-			failCount++
-			pterm.Warning.Printf("SSM command failed on %s with status: %s\n", instanceID, status)
-			continue
-		}
-		
-		successCount++
-
-		lines := strings.Split(stdout, "\n")
-		var gpuModel string
-		var osType string
-		var foundAIProcs []string
-		var modelFiles []string
-		var aiPackages []string
-		var apiKeys []string
-		var aiDirs []string
-
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			
-			if strings.HasPrefix(line, "OS|") {
-				osType = strings.TrimPrefix(line, "OS|")
-			} else if strings.HasPrefix(line, "GPU|") {
-				gpuModel = strings.TrimPrefix(line, "GPU|")
-			} else if strings.HasPrefix(line, "PROCESS|") {
-				parts := strings.SplitN(line, "|", 3)
-				if len(parts) == 3 {
-					cmdArgs := parts[2]
-
-					if !strings.Contains(cmdArgs, "ssm-agent") && 
-					   !strings.Contains(cmdArgs, "pgrep") && 
-					   !strings.Contains(cmdArgs, "cfn-hup") &&
-					   !strings.Contains(cmdArgs, "/bin/sh") {
-						foundAIProcs = append(foundAIProcs, cmdArgs)
-					}
-				}
-			} else if strings.HasPrefix(line, "MODEL_FILE|") {
-				modelFiles = append(modelFiles, strings.TrimPrefix(line, "MODEL_FILE|"))
-			} else if strings.HasPrefix(line, "PIP_PACKAGE|") {
-				aiPackages = append(aiPackages, strings.TrimPrefix(line, "PIP_PACKAGE|"))
-			} else if strings.HasPrefix(line, "API_KEY|") {
-				apiKeys = append(apiKeys, strings.TrimPrefix(line, "API_KEY|"))
-			} else if strings.HasPrefix(line, "AI_DIR|") {
-				aiDirs = append(aiDirs, strings.TrimPrefix(line, "AI_DIR|"))
-			} else if strings.HasPrefix(line, "JUPYTER_NOAUTH|") {
-				findings = append(findings, models.Finding{
-					InstanceID:  instanceID,
-					Region:      s.Client.Region,
-					Risk:        models.RiskCritical,
-					Service:     "Jupyter Notebook",
-					Description: "Jupyter running without authentication",
-					Evidence:    strings.TrimPrefix(line, "JUPYTER_NOAUTH|"),
-				})
+		for _, info := range page.InstanceInformationList {
+			if info.PingStatus == types.PingStatusOnline {
+				online[aws.ToString(info.InstanceId)] = true
 			}
 		}
+	}
 
-		if osType != "" && osType != "Linux" {
-			findings = append(findings, models.Finding{
-				InstanceID:  instanceID,
-				Region:      s.Client.Region,
-				Risk:        models.RiskLow,
-				Service:     "OS Compatibility",
-				Description: fmt.Sprintf("Instance running %s (deep scan limited)", osType),
-				Evidence:    "Deep scan optimized for Linux only",
-			})
+	for _, id := range instanceIDs {
+		if online[id] {
+			targets = append(targets, id)
+		} else {
+			unmanaged = append(unmanaged, id)
 		}
-		if gpuModel != "" && gpuModel != "Unknown" {
-			findings = append(findings, models.Finding{
-				InstanceID:  instanceID,
-				Region:      s.Client.Region,
-				Risk:        models.RiskMedium,
-				Service:     "GPU Detected",
-				Description: fmt.Sprintf("NVIDIA GPU present: %s", gpuModel),
-				Evidence:    "Potential AI/ML workload infrastructure",
+	}
+	return targets, unmanaged
+}
+
+// sendDeepScanCommands sends the script in batches of ssmMaxTargets, the
+// SendCommand limit. Batches that fail are reported in the returned error;
+// the invocations of the batches that were sent are still returned.
+func (s *Scanner) sendDeepScanCommands(ctx context.Context, instanceIDs []string, script string) ([]ssmInvocation, error) {
+	var invocations []ssmInvocation
+	var errs []error
+
+	for start := 0; start < len(instanceIDs); start += ssmMaxTargets {
+		batch := instanceIDs[start:min(start+ssmMaxTargets, len(instanceIDs))]
+
+		var out *ssm.SendCommandOutput
+		err := retryThrottled(ctx, func() error {
+			var err error
+			out, err = s.Client.SSM.SendCommand(ctx, &ssm.SendCommandInput{
+				InstanceIds:  batch,
+				DocumentName: aws.String("AWS-RunShellScript"),
+				Parameters: map[string][]string{
+					"commands": {script},
+				},
+				TimeoutSeconds: aws.Int32(60),
 			})
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send SSM command to %d instances: %w", len(batch), err))
+			continue
+		}
+		if out.Command == nil || out.Command.CommandId == nil {
+			errs = append(errs, fmt.Errorf("ssm SendCommand returned empty command id"))
+			continue
 		}
 
-		if len(modelFiles) > 0 {
-			findings = append(findings, models.Finding{
-				InstanceID:  instanceID,
-				Region:      s.Client.Region,
-				Risk:        models.RiskHigh,
-				Service:     "AI Model Files",
-				Description: fmt.Sprintf("Found %d model files on disk", len(modelFiles)),
-				Evidence:    fmt.Sprintf("Files: %s", strings.Join(modelFiles[:min(3, len(modelFiles))], ", ")),
-			})
+		for _, id := range batch {
+			invocations = append(invocations, ssmInvocation{commandID: *out.Command.CommandId, instanceID: id})
 		}
+	}
+
+	return invocations, errors.Join(errs...)
+}
 
-		if len(aiPackages) > 0 {
+// collectDeepScan waits for one instance's output and parses it. ok is false
+// when the invocation did not succeed.
+func (s *Scanner) collectDeepScan(ctx context.Context, inv ssmInvocation) (findings []models.Finding, ok bool) {
+	instanceID := inv.instanceID
+
+	stdout, status, err := s.waitCommandOutput(ctx, inv.commandID, instanceID, ssmInvocationTimeout)
+	if err != nil {
+		pterm.Warning.Printf("SSM failed on %s: %v\n", instanceID, err)
+		return []models.Finding{{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        models.RiskLow,
+			Service:     "SSM Agent",
+			Description: "Deep scan failed - SSM may not be installed",
+			Evidence:    fmt.Sprintf("Error: %v", err),
+		}}, false
+	}
+
+	if status != types.CommandInvocationStatusSuccess {
+		pterm.Warning.Printf("SSM command failed on %s with status: %s\n", instanceID, status)
+		return nil, false
+	}
+
+	return s.parseDeepScanOutput(instanceID, stdout), true
+}
+
+// parseDeepScanOutput turns the script's PREFIX|value lines into findings.
+func (s *Scanner) parseDeepScanOutput(instanceID, stdout string) []models.Finding {
+	var findings []models.Finding
+
+	lines := strings.Split(stdout, "\n")
+	var gpuModel string
+	var osType string
+	var foundAIProcs []string
+	var modelFiles []string
+	var aiPackages []string
+	var apiKeys []string
+	var aiDirs []string
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "OS|") {
+			osType = strings.TrimPrefix(line, "OS|")
+		} else if strings.HasPrefix(line, "GPU|") {
+			gpuModel = strings.TrimPrefix(line, "GPU|")
+		} else if strings.HasPrefix(line, "PROCESS|") {
+			parts := strings.SplitN(line, "|", 3)
+			if len(parts) == 3 {
+				cmdArgs := parts[2]
+
+				if !strings.Contains(cmdArgs, "ssm-agent") &&
+					!strings.Contains(cmdArgs, "pgrep") &&
+					!strings.Contains(cmdArgs, "cfn-hup") &&
+					!strings.Contains(cmdArgs, "/bin/sh") {
+					foundAIProcs = append(foundAIProcs, cmdArgs)
+				}
+			}
+		} else if strings.HasPrefix(line, "MODEL_FILE|") {
+			modelFiles = append(modelFiles, strings.TrimPrefix(line, "MODEL_FILE|"))
+		} else if strings.HasPrefix(line, "PIP_PACKAGE|") {
+			aiPackages = append(aiPackages, strings.TrimPrefix(line, "PIP_PACKAGE|"))
+		} else if strings.HasPrefix(line, "API_KEY|") {
+			apiKeys = append(apiKeys, strings.TrimPrefix(line, "API_KEY|"))
+		} else if strings.HasPrefix(line, "AI_DIR|") {
+			aiDirs = append(aiDirs, strings.TrimPrefix(line, "AI_DIR|"))
+		} else if strings.HasPrefix(line, "JUPYTER_NOAUTH|") {
 			findings = append(findings, models.Finding{
 				InstanceID:  instanceID,
 				Region:      s.Client.Region,
-				Risk:        models.RiskMedium,
-				Service:     "AI Python Packages",
-				Description: fmt.Sprintf("Found %d AI/ML packages installed", len(aiPackages)),
-				Evidence:    strings.Join(aiPackages[:min(3, len(aiPackages))], ", "),
+				Risk:        models.RiskCritical,
+				Service:     "Jupyter Notebook",
+				Description: "Jupyter running without authentication",
+				Evidence:    strings.TrimPrefix(line, "JUPYTER_NOAUTH|"),
 			})
 		}
+	}
 
-		if len(apiKeys) > 0 {
-			for _, key := range apiKeys {
-				findings = append(findings, models.Finding{
-					InstanceID:  instanceID,
-					Region:      s.Client.Region,
-					Risk:        models.RiskCritical,
-					Service:     "Exposed API Key",
-					Description: "API key found in environment variables",
-					Evidence:    maskAPIKey(key),
-				})
-			}
-		}
+	if osType != "" && osType != "Linux" {
+		findings = append(findings, models.Finding{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        models.RiskLow,
+			Service:     "OS Compatibility",
+			Description: fmt.Sprintf("Instance running %s (deep scan limited)", osType),
+			Evidence:    "Deep scan optimized for Linux only",
+		})
+	}
+	if gpuModel != "" && gpuModel != "Unknown" {
+		findings = append(findings, models.Finding{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        models.RiskMedium,
+			Service:     "GPU Detected",
+			Description: fmt.Sprintf("NVIDIA GPU present: %s", gpuModel),
+			Evidence:    "Potential AI/ML workload infrastructure",
+		})
+	}
+
+	if len(modelFiles) > 0 {
+		findings = append(findings, models.Finding{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        models.RiskHigh,
+			Service:     "AI Model Files",
+			Description: fmt.Sprintf("Found %d model files on disk", len(modelFiles)),
+			Evidence:    fmt.Sprintf("Files: %s", strings.Join(modelFiles[:min(3, len(modelFiles))], ", ")),
+		})
+	}
 
-		if len(aiDirs) > 0 {
+	if len(aiPackages) > 0 {
+		findings = append(findings, models.Finding{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        models.RiskMedium,
+			Service:     "AI Python Packages",
+			Description: fmt.Sprintf("Found %d AI/ML packages installed", len(aiPackages)),
+			Evidence:    strings.Join(aiPackages[:min(3, len(aiPackages))], ", "),
+		})
+	}
+
+	if len(apiKeys) > 0 {
+		for _, key := range apiKeys {
 			findings = append(findings, models.Finding{
 				InstanceID:  instanceID,
 				Region:      s.Client.Region,
-				Risk:        models.RiskMedium,
-				Service:     "AI Model Cache",
-				Description: fmt.Sprintf("Found %d AI model directories", len(aiDirs)),
-				Evidence:    strings.Join(aiDirs, ", "),
+				Risk:        models.RiskCritical,
+				Service:     "Exposed API Key",
+				Description: "API key found in environment variables",
+				Evidence:    maskAPIKey(key),
 			})
 		}
+	}
 
-		for _, procCmd := range foundAIProcs {
-			risk := models.RiskMedium
-			serviceName := "Suspicious Process"
-			desc := "Potential AI workload"
-
-			if strings.Contains(procCmd, "vllm") {
-				serviceName = "vLLM Inference Server"
-				risk = models.RiskHigh
-				modelName := extractArgValue(procCmd, "--model")
-				if modelName == "" {
-					fields := strings.Fields(procCmd)
-					for i, f := range fields {
-						if f == "serve" && i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "-") {
-							modelName = fields[i+1]
-							break
-						}
+	if len(aiDirs) > 0 {
+		findings = append(findings, models.Finding{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        models.RiskMedium,
+			Service:     "AI Model Cache",
+			Description: fmt.Sprintf("Found %d AI model directories", len(aiDirs)),
+			Evidence:    strings.Join(aiDirs, ", "),
+		})
+	}
+
+	for _, procCmd := range foundAIProcs {
+		risk := models.RiskMedium
+		serviceName := "Suspicious Process"
+		desc := "Potential AI workload"
+
+		if strings.Contains(procCmd, "vllm") {
+			serviceName = "vLLM Inference Server"
+			risk = models.RiskHigh
+			modelName := extractArgValue(procCmd, "--model")
+			if modelName == "" {
+				fields := strings.Fields(procCmd)
+				for i, f := range fields {
+					if f == "serve" && i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "-") {
+						modelName = fields[i+1]
+						break
 					}
 				}
-				if modelName != "" {
-					desc = fmt.Sprintf("Serving model: %s", modelName)
-				} else {
-					desc = "Serving unknown model via vLLM"
-				}
-			} else if strings.Contains(procCmd, "ollama serve") {
-				serviceName = "Ollama Service"
-				risk = models.RiskCritical
-				desc = "Active Ollama API"
-			} else if strings.Contains(procCmd, "llama") || strings.Contains(procCmd, "mistral") {
-				serviceName = "LLM Process"
-				risk = models.RiskHigh
-				desc = "Found model name in process args"
-			} else if strings.Contains(procCmd, "streamlit run") {
-				serviceName = "Streamlit App"
-				risk = models.RiskHigh
-				desc = "Interactive ML dashboard running"
-			} else if strings.Contains(procCmd, "ray start") {
-				serviceName = "Ray Cluster"
-				risk = models.RiskHigh
-				desc = "Distributed computing framework active"
 			}
-
-			if gpuModel != "" {
-				desc += fmt.Sprintf(" on GPU (%s)", gpuModel)
+			if modelName != "" {
+				desc = fmt.Sprintf("Serving model: %s", modelName)
+			} else {
+				desc = "Serving unknown model via vLLM"
 			}
+		} else if strings.Contains(procCmd, "ollama serve") {
+			serviceName = "Ollama Service"
+			risk = models.RiskCritical
+			desc = "Active Ollama API"
+		} else if strings.Contains(procCmd, "llama") || strings.Contains(procCmd, "mistral") {
+			serviceName = "LLM Process"
+			risk = models.RiskHigh
+			desc = "Found model name in process args"
+		} else if strings.Contains(procCmd, "streamlit run") {
+			serviceName = "Streamlit App"
+			risk = models.RiskHigh
+			desc = "Interactive ML dashboard running"
+		} else if strings.Contains(procCmd, "ray start") {
+			serviceName = "Ray Cluster"
+			risk = models.RiskHigh
+			desc = "Distributed computing framework active"
+		}
 
-			findings = append(findings, models.Finding{
-				InstanceID:  instanceID,
-				Region:      s.Client.Region,
-				Risk:        risk,
-				Service:     serviceName,
-				Description: desc,
-				Evidence:    fmt.Sprintf("Cmd: %s", truncate(procCmd, 120)),
-			})
+		if gpuModel != "" {
+			desc += fmt.Sprintf(" on GPU (%s)", gpuModel)
 		}
-	}
 
-	pterm.Info.Printf("SSM Deep Scan: %d succeeded, %d failed\n", successCount, failCount)
+		findings = append(findings, models.Finding{
+			InstanceID:  instanceID,
+			Region:      s.Client.Region,
+			Risk:        risk,
+			Service:     serviceName,
+			Description: desc,
+			Evidence:    fmt.Sprintf("Cmd: %s", truncate(procCmd, 120)),
+		})
+	}
 
-	return findings, nil
+	return findings
 }
 
 func (s *Scanner) waitCommandOutput(ctx context.Context, commandID, instanceID string, timeout time.Duration) (string, types.CommandInvocationStatus, error) {
 	deadline := time.Now().Add(timeout)
+	delay := ssmPollInterval
 
 	for {
 		if time.Now().After(deadline) {
@@ -325,30 +511,66 @@ func (s *Scanner) waitCommandOutput(ctx context.Context, commandID, instanceID s
 			PluginName: aws.String("aws:runShellScript"),
 		})
 		if err != nil {
-			select {
-			case <-ctx.Done():
-				return "", types.CommandInvocationStatusCancelled, ctx.Err()
-			case <-time.After(1 * time.Second):
-				continue
+			// InvocationDoesNotExist is normal right after SendCommand;
+			// throttling means every poller should slow down.
+			if isThrottleError(err) {
+				delay = min(delay*2, ssmMaxPollInterval)
+			}
+			if err := sleepCtx(ctx, withJitter(delay)); err != nil {
+				return "", types.CommandInvocationStatusCancelled, err
 			}
+			continue
 		}
 
 		switch res.Status {
 		case types.CommandInvocationStatusPending,
 			types.CommandInvocationStatusInProgress,
 			types.CommandInvocationStatusDelayed:
-			select {
-			case <-ctx.Done():
-				return "", types.CommandInvocationStatusCancelled, ctx.Err()
-			case <-time.After(1 * time.Second):
-				continue
+			if err := sleepCtx(ctx, withJitter(delay)); err != nil {
+				return "", types.CommandInvocationStatusCancelled, err
 			}
+			continue
 		default:
 			return aws.ToString(res.StandardOutputContent), res.Status, nil
 		}
 	}
 }
 
+// retryThrottled calls fn until it succeeds, fails with a non-throttling
+// error, or ssmMaxRetries throttled attempts have been made, backing off
+// exponentially in between.
+func retryThrottled(ctx context.Context, fn func() error) error {
+	delay := ssmPollInterval
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !isThrottleError(err) || attempt >= ssmMaxRetries {
+			return err
+		}
+		if err := sleepCtx(ctx, withJitter(delay)); err != nil {
+			return err
+		}
+		delay = min(delay*2, ssmMaxPollInterval)
+	}
+}
+
+func isThrottleError(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// withJitter adds up to 50% random delay so concurrent pollers spread out.
+func withJitter(d time.Duration) time.Duration {
+	return d + rand.N(d/2+1)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
 func extractArgValue(cmdLine, flag string) string {
 	fields := strings.Fields(cmdLine)
 	for i, f := range fields {
@@ -372,10 +594,16 @@ func truncate(s string, max int) string {
 func maskAPIKey(keyLine string) string {
 	parts := strings.SplitN(keyLine, "=", 2)
 	if len(parts) == 2 {
-		key := parts[1]
-		if len(key) > 8 {
-			return fmt.Sprintf("%s=%s***%s", parts[0], key[:4], key[len(key)-4:])
-		}
+		return parts[0] + "=" + maskSecret(parts[1])
 	}
 	return keyLine
-}
\ No newline at end of file
+}
+
+// maskSecret keeps the first and last four characters of secrets longer
+// than eight.
+func maskSecret(key string) string {
+	if len(key) > 8 {
+		return fmt.Sprintf("%s***%s", key[:4], key[len(key)-4:])
+	}
+	return key
+}
@@ -0,0 +1,223 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestDeepScanParsesOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected map[string]models.RiskLevel
+		evidence map[string]string
+	}{
+		{
+			name: "vllm on gpu",
+			output: strings.Join([]string{
+				"OS|Linux",
+				"GPU|NVIDIA A10G",
+				"PROCESS|42|python -m vllm.entrypoints.openai.api_server --model meta-llama/Llama-3-8b",
+			}, "\n"),
+			expected: map[string]models.RiskLevel{
+				"GPU Detected":          models.RiskMedium,
+				"vLLM Inference Server": models.RiskHigh,
+			},
+		},
+		{
+			name:     "ollama serve",
+			output:   "OS|Linux\nPROCESS|7|/usr/local/bin/ollama serve",
+			expected: map[string]models.RiskLevel{"Ollama Service": models.RiskCritical},
+		},
+		{
+			name:     "agent processes are ignored",
+			output:   "OS|Linux\nPROCESS|1|/usr/bin/amazon-ssm-agent\nPROCESS|2|/bin/sh -c pgrep ray",
+			expected: map[string]models.RiskLevel{},
+		},
+		{
+			name:     "api key is masked",
+			output:   "OS|Linux\nAPI_KEY|OPENAI_API_KEY=sk-abcdefghijklmnop",
+			expected: map[string]models.RiskLevel{"Exposed API Key": models.RiskCritical},
+			evidence: map[string]string{"Exposed API Key": "OPENAI_API_KEY=sk-a***mnop"},
+		},
+		{
+			name: "model files, packages and jupyter",
+			output: strings.Join([]string{
+				"OS|Linux",
+				"MODEL_FILE|/opt/models/llama.gguf",
+				"PIP_PACKAGE|torch 2.1.0",
+				"AI_DIR|/opt/models|12G",
+				"JUPYTER_NOAUTH|http://0.0.0.0:8888/",
+			}, "\n"),
+			expected: map[string]models.RiskLevel{
+				"AI Model Files":     models.RiskHigh,
+				"AI Python Packages": models.RiskMedium,
+				"AI Model Cache":     models.RiskMedium,
+				"Jupyter Notebook":   models.RiskCritical,
+			},
+		},
+		{
+			name:     "non-linux os",
+			output:   "OS|Darwin",
+			expected: map[string]models.RiskLevel{"OS Compatibility": models.RiskLow},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ssm := &fake.SSM{Outputs: map[string]string{"i-1": tt.output}}
+			scn := newTestScanner(nil, ssm, nil)
+
+			findings, err := scn.DeepScan(context.Background(), []string{"i-1"}, nil)
+			if err != nil {
+				t.Fatalf("DeepScan: %v", err)
+			}
+
+			got := findingsByService(findings)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("got %d findings %v, want %d", len(got), got, len(tt.expected))
+			}
+			for service, risk := range tt.expected {
+				if got[service].Risk != risk {
+					t.Errorf("%s: risk = %q, want %s", service, got[service].Risk, risk)
+				}
+			}
+			for service, evidence := range tt.evidence {
+				if got[service].Evidence != evidence {
+					t.Errorf("%s: evidence = %q, want %q", service, got[service].Evidence, evidence)
+				}
+			}
+		})
+	}
+}
+
+func TestDeepScanFailedInvocation(t *testing.T) {
+	ssm := &fake.SSM{
+		Outputs:  map[string]string{"i-ok": "OS|Linux"},
+		Statuses: map[string]ssmtypes.CommandInvocationStatus{"i-bad": ssmtypes.CommandInvocationStatusFailed},
+	}
+	scn := newTestScanner(nil, ssm, nil)
+
+	findings, err := scn.DeepScan(context.Background(), []string{"i-ok", "i-bad"}, nil)
+	if err != nil {
+		t.Fatalf("DeepScan: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+	if len(ssm.Sent) != 1 || len(ssm.Sent[0].InstanceIds) != 2 {
+		t.Errorf("unexpected SendCommand calls: %v", ssm.Sent)
+	}
+}
+
+func TestMaskAPIKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"OPENAI_API_KEY=sk-1234567890abcdef", "OPENAI_API_KEY=sk-1***cdef"},
+		{"SHORT=abc", "SHORT=abc"},
+		{"no equals sign", "no equals sign"},
+	}
+
+	for _, tt := range tests {
+		if got := maskAPIKey(tt.in); got != tt.want {
+			t.Errorf("maskAPIKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExtractArgValue(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		flag string
+		want string
+	}{
+		{"vllm serve --model llama3 --port 8000", "--model", "llama3"},
+		{"vllm serve --model=mistral-7b", "--model", "mistral-7b"},
+		{"vllm serve", "--model", ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractArgValue(tt.cmd, tt.flag); got != tt.want {
+			t.Errorf("extractArgValue(%q, %q) = %q, want %q", tt.cmd, tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestDeepScanBatchesAndFiltersUnmanaged(t *testing.T) {
+	outputs := map[string]string{}
+	var ids []string
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("i-%03d", i)
+		ids = append(ids, id)
+		outputs[id] = "OS|Linux"
+	}
+	ids = append(ids, "i-offline", "i-unknown")
+
+	ping := map[string]ssmtypes.PingStatus{"i-offline": ssmtypes.PingStatusConnectionLost}
+	for id := range outputs {
+		ping[id] = ssmtypes.PingStatusOnline
+	}
+
+	ssm := &fake.SSM{Outputs: outputs, PingStatus: ping}
+	scn := newTestScanner(nil, ssm, nil)
+
+	findings, err := scn.DeepScan(context.Background(), ids, nil)
+	if err != nil {
+		t.Fatalf("DeepScan: %v", err)
+	}
+
+	if len(ssm.Sent) != 3 {
+		t.Fatalf("SendCommand calls = %d, want 3", len(ssm.Sent))
+	}
+	sent := 0
+	for _, in := range ssm.Sent {
+		if len(in.InstanceIds) > 50 {
+			t.Errorf("batch of %d exceeds SSM limit", len(in.InstanceIds))
+		}
+		sent += len(in.InstanceIds)
+	}
+	if sent != 120 {
+		t.Errorf("sent to %d instances, want 120", sent)
+	}
+
+	skipped := map[string]bool{}
+	for _, f := range findings {
+		if f.Service == "SSM Agent" {
+			skipped[f.InstanceID] = true
+		}
+	}
+	if !skipped["i-offline"] || !skipped["i-unknown"] || len(skipped) != 2 {
+		t.Errorf("skipped = %v, want i-offline and i-unknown", skipped)
+	}
+}
+
+func TestDeepScanBacksOffWhenThrottled(t *testing.T) {
+	defer func(d time.Duration) { ssmPollInterval = d }(ssmPollInterval)
+	ssmPollInterval = time.Millisecond
+
+	ssm := &fake.SSM{
+		Outputs:  map[string]string{"i-1": "OS|Linux\nPROCESS|7|ollama serve"},
+		Throttle: 3,
+	}
+	scn := newTestScanner(nil, ssm, nil)
+
+	findings, err := scn.DeepScan(context.Background(), []string{"i-1"}, nil)
+	if err != nil {
+		t.Fatalf("DeepScan: %v", err)
+	}
+	if _, ok := findingsByService(findings)["Ollama Service"]; !ok {
+		t.Errorf("expected Ollama finding after throttling, got %v", findings)
+	}
+	if ssm.Polls < 4 {
+		t.Errorf("polls = %d, want at least 4", ssm.Polls)
+	}
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	client "github.com/K0NGR3SS/ghostweights/internal/aws"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+const optInStatusNotOptedIn = "not-opted-in"
+
+// RegionDiscovery is the result of DiscoverRegions. All lists are sorted.
+type RegionDiscovery struct {
+	// Enabled regions matched the include patterns and not the exclude ones.
+	Enabled []string
+	// NotOptedIn regions exist but have not been enabled for the account.
+	NotOptedIn []string
+	// Filtered regions are enabled but were removed by the patterns.
+	Filtered []string
+}
+
+// DiscoverRegions lists every region via DescribeRegions and splits them
+// into enabled, not-opted-in and filtered sets. Include and exclude take
+// wildcard patterns such as "eu-*"; an empty include list matches all.
+func DiscoverRegions(ctx context.Context, api client.EC2API, include, exclude []string) (*RegionDiscovery, error) {
+	out, err := api.DescribeRegions(ctx, &ec2.DescribeRegionsInput{
+		AllRegions: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe regions: %w", err)
+	}
+
+	result := &RegionDiscovery{}
+	for _, r := range out.Regions {
+		name := aws.ToString(r.RegionName)
+		if name == "" {
+			continue
+		}
+
+		switch {
+		case aws.ToString(r.OptInStatus) == optInStatusNotOptedIn:
+			result.NotOptedIn = append(result.NotOptedIn, name)
+		case !matchesAny(include, name, true) || matchesAny(exclude, name, false):
+			result.Filtered = append(result.Filtered, name)
+		default:
+			result.Enabled = append(result.Enabled, name)
+		}
+	}
+
+	sort.Strings(result.Enabled)
+	sort.Strings(result.NotOptedIn)
+	sort.Strings(result.Filtered)
+
+	return result, nil
+}
+
+// matchesAny reports whether name matches one of patterns, or returns
+// ifEmpty when there are no patterns.
+func matchesAny(patterns []string, name string, ifEmpty bool) bool {
+	if len(patterns) == 0 {
+		return ifEmpty
+	}
+	for _, p := range patterns {
+		if globMatch(p, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestDiscoverRegions(t *testing.T) {
+	ec2 := &fake.EC2{Regions: []types.Region{
+		{RegionName: aws.String("us-east-1"), OptInStatus: aws.String("opt-in-not-required")},
+		{RegionName: aws.String("eu-west-1"), OptInStatus: aws.String("opt-in-not-required")},
+		{RegionName: aws.String("eu-central-2"), OptInStatus: aws.String("opted-in")},
+		{RegionName: aws.String("il-central-1"), OptInStatus: aws.String("not-opted-in")},
+		{RegionName: aws.String("ap-south-2"), OptInStatus: aws.String("opted-in")},
+	}}
+
+	tests := []struct {
+		name         string
+		include      []string
+		exclude      []string
+		wantEnabled  []string
+		wantFiltered []string
+	}{
+		{
+			name:        "all enabled",
+			wantEnabled: []string{"ap-south-2", "eu-central-2", "eu-west-1", "us-east-1"},
+		},
+		{
+			name:         "include pattern",
+			include:      []string{"eu-*"},
+			wantEnabled:  []string{"eu-central-2", "eu-west-1"},
+			wantFiltered: []string{"ap-south-2", "us-east-1"},
+		},
+		{
+			name:         "exclude pattern",
+			exclude:      []string{"ap-*", "us-east-?"},
+			wantEnabled:  []string{"eu-central-2", "eu-west-1"},
+			wantFiltered: []string{"ap-south-2", "us-east-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiscoverRegions(context.Background(), ec2, tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("DiscoverRegions: %v", err)
+			}
+			if !reflect.DeepEqual(got.Enabled, tt.wantEnabled) {
+				t.Errorf("Enabled = %v, want %v", got.Enabled, tt.wantEnabled)
+			}
+			if !reflect.DeepEqual(got.Filtered, tt.wantFiltered) {
+				t.Errorf("Filtered = %v, want %v", got.Filtered, tt.wantFiltered)
+			}
+			if !reflect.DeepEqual(got.NotOptedIn, []string{"il-central-1"}) {
+				t.Errorf("NotOptedIn = %v, want [il-central-1]", got.NotOptedIn)
+			}
+		})
+	}
+}
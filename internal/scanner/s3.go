@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/pterm/pterm"
@@ -17,22 +18,34 @@ var aiKeywords = []string{
 	"llm", "huggingface", "ollama", "weights", "checkpoint",
 }
 
+// modelExtensions are file extensions of serialized model weights.
+var modelExtensions = []string{".safetensors", ".gguf", ".bin", ".pt", ".pth", ".h5", ".pb"}
+
+func isModelFile(name string) bool {
+	for _, ext := range modelExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Scanner) ScanS3Buckets(ctx context.Context, spinner *pterm.SpinnerPrinter) ([]models.Finding, error) {
 	var findings []models.Finding
 
-	spinner.UpdateText("Scanning S3 buckets for AI artifacts...")
-	s3Client := s3.NewFromConfig(s.Client.Config)
+	ui.UpdateSpinner(spinner, "Scanning S3 buckets for AI artifacts...")
+	s3Client := s.Client.S3
 	listResult, err := s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list S3 buckets: %w", err)
 	}
 
-	spinner.UpdateText(fmt.Sprintf("Analyzing %d S3 buckets...", len(listResult.Buckets)))
+	ui.UpdateSpinner(spinner, fmt.Sprintf("Analyzing %d S3 buckets...", len(listResult.Buckets)))
 
 	for idx, bucket := range listResult.Buckets {
 		bucketName := aws.ToString(bucket.Name)
 		
-		spinner.UpdateText(fmt.Sprintf("Checking bucket %s (%d/%d)...", bucketName, idx+1, len(listResult.Buckets)))
+		ui.UpdateSpinner(spinner, fmt.Sprintf("Checking bucket %s (%d/%d)...", bucketName, idx+1, len(listResult.Buckets)))
 
 		isAIRelated := false
 		for _, keyword := range aiKeywords {
@@ -46,52 +59,19 @@ func (s *Scanner) ScanS3Buckets(ctx context.Context, spinner *pterm.SpinnerPrint
 			continue
 		}
 
-		locationResult, err := s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
-			Bucket: aws.String(bucketName),
-		})
+		posture, err := s.bucketPosture(ctx, bucketName)
 		if err != nil {
 			continue
 		}
-
-		bucketRegion := string(locationResult.LocationConstraint)
-		if bucketRegion == "" {
-			bucketRegion = "us-east-1"
-		}
-
-		aclResult, err := s3Client.GetBucketAcl(ctx, &s3.GetBucketAclInput{
-			Bucket: aws.String(bucketName),
-		})
-
-		isPublic := false
-		if err == nil {
-			for _, grant := range aclResult.Grants {
-				if grant.Grantee != nil && grant.Grantee.URI != nil {
-					uri := *grant.Grantee.URI
-					if strings.Contains(uri, "AllUsers") || strings.Contains(uri, "AuthenticatedUsers") {
-						isPublic = true
-						break
-					}
-				}
-			}
-		}
-		policyResult, err := s3Client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
-			Bucket: aws.String(bucketName),
-		})
-		if err == nil && policyResult.Policy != nil {
-			policyStr := aws.ToString(policyResult.Policy)
-			if strings.Contains(policyStr, `"Principal":"*"`) || strings.Contains(policyStr, `"Principal":{"AWS":"*"}`) {
-				isPublic = true
-			}
+		bucketRegion, isPublic, isEncrypted := posture.Region, posture.Public, posture.Encrypted
+		inBucketRegion := func(o *s3.Options) {
+			o.Region = bucketRegion
 		}
 
-		encryptionResult, err := s3Client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
-			Bucket: aws.String(bucketName),
-		})
-		isEncrypted := err == nil && len(encryptionResult.ServerSideEncryptionConfiguration.Rules) > 0
 		listObjResult, err := s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
 			Bucket:  aws.String(bucketName),
 			MaxKeys: aws.Int32(100),
-		})
+		}, inBucketRegion)
 
 		var modelFiles []string
 		var totalSize int64
@@ -100,13 +80,7 @@ func (s *Scanner) ScanS3Buckets(ctx context.Context, spinner *pterm.SpinnerPrint
 				key := aws.ToString(obj.Key)
 				totalSize += aws.ToInt64(obj.Size)
 
-				if strings.HasSuffix(key, ".safetensors") ||
-					strings.HasSuffix(key, ".gguf") ||
-					strings.HasSuffix(key, ".bin") ||
-					strings.HasSuffix(key, ".pt") ||
-					strings.HasSuffix(key, ".pth") ||
-					strings.HasSuffix(key, ".h5") ||
-					strings.HasSuffix(key, ".pb") {
+				if isModelFile(key) {
 					modelFiles = append(modelFiles, key)
 				}
 			}
@@ -149,3 +123,66 @@ func (s *Scanner) ScanS3Buckets(ctx context.Context, spinner *pterm.SpinnerPrint
 
 	return findings, nil
 }
+
+// bucketPosture is what the scanner knows about a bucket's exposure.
+type bucketPosture struct {
+	Region    string
+	Public    bool
+	Encrypted bool
+}
+
+// bucketPosture looks up a bucket's region, whether its ACL or policy grants
+// public access and whether default encryption is configured. It fails only
+// when the bucket's location can't be read, e.g. when it belongs to another
+// account.
+func (s *Scanner) bucketPosture(ctx context.Context, bucketName string) (bucketPosture, error) {
+	s3Client := s.Client.S3
+
+	locationResult, err := s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return bucketPosture{}, err
+	}
+
+	posture := bucketPosture{Region: string(locationResult.LocationConstraint)}
+	if posture.Region == "" {
+		posture.Region = "us-east-1"
+	}
+	inBucketRegion := func(o *s3.Options) {
+		o.Region = posture.Region
+	}
+
+	aclResult, err := s3Client.GetBucketAcl(ctx, &s3.GetBucketAclInput{
+		Bucket: aws.String(bucketName),
+	}, inBucketRegion)
+	if err == nil {
+		for _, grant := range aclResult.Grants {
+			if grant.Grantee != nil && grant.Grantee.URI != nil {
+				uri := *grant.Grantee.URI
+				if strings.Contains(uri, "AllUsers") || strings.Contains(uri, "AuthenticatedUsers") {
+					posture.Public = true
+					break
+				}
+			}
+		}
+	}
+
+	policyResult, err := s3Client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucketName),
+	}, inBucketRegion)
+	if err == nil && policyResult.Policy != nil {
+		policyStr := aws.ToString(policyResult.Policy)
+		if strings.Contains(policyStr, `"Principal":"*"`) || strings.Contains(policyStr, `"Principal":{"AWS":"*"}`) {
+			posture.Public = true
+		}
+	}
+
+	encryptionResult, err := s3Client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucketName),
+	}, inBucketRegion)
+	posture.Encrypted = err == nil && encryptionResult.ServerSideEncryptionConfiguration != nil &&
+		len(encryptionResult.ServerSideEncryptionConfiguration.Rules) > 0
+
+	return posture, nil
+}
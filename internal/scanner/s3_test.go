@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestScanS3BucketsRisk(t *testing.T) {
+	allUsers := s3types.Grant{Grantee: &s3types.Grantee{URI: aws.String("http://acs.amazonaws.com/groups/global/AllUsers")}}
+
+	tests := []struct {
+		name     string
+		bucket   string
+		state    fake.Bucket
+		wantRisk models.RiskLevel
+		wantDesc string
+	}{
+		{
+			name:     "public acl",
+			bucket:   "team-models",
+			state:    fake.Bucket{Region: "eu-west-1", Grants: []s3types.Grant{allUsers}, Encrypted: true},
+			wantRisk: models.RiskCritical,
+			wantDesc: "PUBLIC ACCESS",
+		},
+		{
+			name:     "public policy",
+			bucket:   "rag-datasets",
+			state:    fake.Bucket{Region: "us-east-1", Policy: `{"Statement":[{"Effect":"Allow","Principal":"*"}]}`, Encrypted: true},
+			wantRisk: models.RiskCritical,
+			wantDesc: "PUBLIC ACCESS",
+		},
+		{
+			name:     "unencrypted",
+			bucket:   "llm-weights",
+			state:    fake.Bucket{Region: "us-west-2"},
+			wantRisk: models.RiskHigh,
+			wantDesc: "UNENCRYPTED",
+		},
+		{
+			name:   "private encrypted with model files",
+			bucket: "inference-checkpoints",
+			state: fake.Bucket{Region: "us-east-1", Encrypted: true, Objects: []s3types.Object{
+				{Key: aws.String("llama/model.safetensors"), Size: aws.Int64(1 << 30)},
+				{Key: aws.String("README.md"), Size: aws.Int64(100)},
+			}},
+			wantRisk: models.RiskMedium,
+			wantDesc: "Contains 1 model files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.state
+			s3 := &fake.S3{Buckets: map[string]*fake.Bucket{tt.bucket: &state}}
+			scn := newTestScanner(nil, nil, s3)
+
+			findings, err := scn.ScanS3Buckets(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("ScanS3Buckets: %v", err)
+			}
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1", len(findings))
+			}
+
+			f := findings[0]
+			if f.Risk != tt.wantRisk {
+				t.Errorf("risk = %s, want %s", f.Risk, tt.wantRisk)
+			}
+			if !strings.Contains(f.Description, tt.wantDesc) {
+				t.Errorf("description %q does not contain %q", f.Description, tt.wantDesc)
+			}
+			if f.InstanceID != tt.bucket || f.Region != tt.state.Region {
+				t.Errorf("attribution = %s/%s, want %s/%s", f.InstanceID, f.Region, tt.bucket, tt.state.Region)
+			}
+		})
+	}
+}
+
+func TestScanS3BucketsSkipsUnrelated(t *testing.T) {
+	s3 := &fake.S3{Buckets: map[string]*fake.Bucket{
+		"company-logs": {Region: "us-east-1"},
+	}}
+	scn := newTestScanner(nil, nil, s3)
+
+	findings, err := scn.ScanS3Buckets(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ScanS3Buckets: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
@@ -0,0 +1,339 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+)
+
+// trainingJobLookback limits the training job check to recent jobs; old
+// jobs are immutable and would otherwise dominate the report.
+const trainingJobLookback = 90 * 24 * time.Hour
+
+// ScanSageMaker checks notebook instances, endpoints, Studio domains,
+// recent training jobs and models. A check that fails, e.g. for missing
+// permissions, is logged and the others still run.
+func (s *Scanner) ScanSageMaker(ctx context.Context, target *Target) ([]models.Finding, error) {
+	checks := []struct {
+		name string
+		run  func(context.Context, *Target) ([]models.Finding, error)
+	}{
+		{"notebook instances", s.scanNotebookInstances},
+		{"endpoints", s.scanSageMakerEndpoints},
+		{"Studio domains", s.scanStudioDomains},
+		{"training jobs", s.scanTrainingJobs},
+		{"models", s.scanSageMakerModels},
+	}
+
+	var findings []models.Finding
+	for _, c := range checks {
+		ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Checking SageMaker %s in %s...", c.name, target.Region))
+		found, err := c.run(ctx, target)
+		if err != nil {
+			log.Printf("WARNING: SageMaker %s check failed in %s: %v", c.name, target.Region, err)
+			continue
+		}
+		findings = append(findings, found...)
+	}
+	return findings, nil
+}
+
+func (s *Scanner) scanNotebookInstances(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	paginator := sagemaker.NewListNotebookInstancesPaginator(s.Client.SageMaker, &sagemaker.ListNotebookInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range page.NotebookInstances {
+			name := aws.ToString(summary.NotebookInstanceName)
+			nb, err := s.Client.SageMaker.DescribeNotebookInstance(ctx, &sagemaker.DescribeNotebookInstanceInput{
+				NotebookInstanceName: summary.NotebookInstanceName,
+			})
+			if err != nil {
+				log.Printf("WARNING: Failed to describe notebook instance %s: %v", name, err)
+				continue
+			}
+
+			base := models.Finding{InstanceID: name, Region: target.Region, PublicIP: "N/A", PrivateIP: "N/A", NameTag: name}
+			where := fmt.Sprintf("Notebook %s (%s, %s)", name, nb.InstanceType, nb.NotebookInstanceStatus)
+
+			if nb.DirectInternetAccess == smtypes.DirectInternetAccessEnabled {
+				f := base
+				f.Risk = models.RiskHigh
+				f.Service = "SageMaker Notebook Internet Access"
+				f.Description = "Notebook instance has direct internet access outside any VPC controls"
+				f.Evidence = where + ": DirectInternetAccess=Enabled"
+				findings = append(findings, f)
+			}
+			if nb.RootAccess == smtypes.RootAccessEnabled {
+				f := base
+				f.Risk = models.RiskMedium
+				f.Service = "SageMaker Notebook Root Access"
+				f.Description = "Notebook users have root on the instance"
+				f.Evidence = where + ": RootAccess=Enabled"
+				findings = append(findings, f)
+			}
+			if aws.ToString(nb.KmsKeyId) == "" {
+				f := base
+				f.Risk = models.RiskMedium
+				f.Service = "SageMaker Notebook Encryption"
+				f.Description = "Notebook storage volume is not encrypted with a customer KMS key"
+				f.Evidence = where + ": no KmsKeyId"
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanSageMakerEndpoints(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	paginator := sagemaker.NewListEndpointsPaginator(s.Client.SageMaker, &sagemaker.ListEndpointsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range page.Endpoints {
+			name := aws.ToString(summary.EndpointName)
+			ep, err := s.Client.SageMaker.DescribeEndpoint(ctx, &sagemaker.DescribeEndpointInput{EndpointName: summary.EndpointName})
+			if err != nil {
+				log.Printf("WARNING: Failed to describe endpoint %s: %v", name, err)
+				continue
+			}
+			cfg, err := s.Client.SageMaker.DescribeEndpointConfig(ctx, &sagemaker.DescribeEndpointConfigInput{EndpointConfigName: ep.EndpointConfigName})
+			if err != nil {
+				log.Printf("WARNING: Failed to describe endpoint config %s: %v", aws.ToString(ep.EndpointConfigName), err)
+				continue
+			}
+
+			f := models.Finding{
+				InstanceID: name,
+				Region:     target.Region,
+				PublicIP:   "N/A",
+				PrivateIP:  "N/A",
+				NameTag:    name,
+				Service:    "SageMaker Endpoint Data Capture",
+			}
+			where := fmt.Sprintf("Endpoint %s (config %s)", name, aws.ToString(ep.EndpointConfigName))
+
+			capture := cfg.DataCaptureConfig
+			switch {
+			case capture == nil || !aws.ToBool(capture.EnableCapture):
+				f.Risk = models.RiskLow
+				f.Description = "Inference requests and responses are not captured for audit"
+				f.Evidence = where + ": data capture disabled"
+			case aws.ToString(capture.KmsKeyId) == "":
+				f.Risk = models.RiskMedium
+				f.Description = "Captured inference payloads are stored without a customer KMS key"
+				f.Evidence = fmt.Sprintf("%s: capturing %d%% to %s without KmsKeyId",
+					where, aws.ToInt32(capture.InitialSamplingPercentage), aws.ToString(capture.DestinationS3Uri))
+			default:
+				continue
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanStudioDomains(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	paginator := sagemaker.NewListDomainsPaginator(s.Client.SageMaker, &sagemaker.ListDomainsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range page.Domains {
+			id := aws.ToString(summary.DomainId)
+			d, err := s.Client.SageMaker.DescribeDomain(ctx, &sagemaker.DescribeDomainInput{DomainId: summary.DomainId})
+			if err != nil {
+				log.Printf("WARNING: Failed to describe Studio domain %s: %v", id, err)
+				continue
+			}
+			if d.AppNetworkAccessType != smtypes.AppNetworkAccessTypePublicInternetOnly {
+				continue
+			}
+
+			findings = append(findings, models.Finding{
+				InstanceID:  id,
+				Region:      target.Region,
+				PublicIP:    "N/A",
+				PrivateIP:   "N/A",
+				NameTag:     aws.ToString(d.DomainName),
+				Risk:        models.RiskHigh,
+				Service:     "SageMaker Studio Public Internet",
+				Description: "Studio apps reach the internet directly instead of through the VPC",
+				Evidence:    fmt.Sprintf("Domain %s (%s): AppNetworkAccessType=PublicInternetOnly, AuthMode=%s", aws.ToString(d.DomainName), id, d.AuthMode),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanTrainingJobs(ctx context.Context, target *Target) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	paginator := sagemaker.NewListTrainingJobsPaginator(s.Client.SageMaker, &sagemaker.ListTrainingJobsInput{
+		CreationTimeAfter: aws.Time(time.Now().Add(-trainingJobLookback)),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range page.TrainingJobSummaries {
+			name := aws.ToString(summary.TrainingJobName)
+			job, err := s.Client.SageMaker.DescribeTrainingJob(ctx, &sagemaker.DescribeTrainingJobInput{TrainingJobName: summary.TrainingJobName})
+			if err != nil {
+				log.Printf("WARNING: Failed to describe training job %s: %v", name, err)
+				continue
+			}
+			if aws.ToBool(job.EnableNetworkIsolation) {
+				continue
+			}
+
+			evidence := fmt.Sprintf("Training job %s (%s): EnableNetworkIsolation=false", name, job.TrainingJobStatus)
+			if job.VpcConfig == nil {
+				evidence += ", no VPC config"
+			}
+			if job.ResourceConfig != nil {
+				evidence += fmt.Sprintf(", %d x %s", aws.ToInt32(job.ResourceConfig.InstanceCount), job.ResourceConfig.InstanceType)
+			}
+
+			findings = append(findings, models.Finding{
+				InstanceID:  name,
+				Region:      target.Region,
+				PublicIP:    "N/A",
+				PrivateIP:   "N/A",
+				NameTag:     name,
+				Risk:        models.RiskMedium,
+				Service:     "SageMaker Training Network Isolation",
+				Description: "Training containers can make outbound network calls with the job's data and credentials",
+				Evidence:    evidence,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func (s *Scanner) scanSageMakerModels(ctx context.Context, target *Target) ([]models.Finding, error) {
+	if s.Client.S3 == nil {
+		return nil, nil
+	}
+
+	type postureResult struct {
+		posture bucketPosture
+		err     error
+	}
+
+	var findings []models.Finding
+	postures := map[string]postureResult{}
+
+	paginator := sagemaker.NewListModelsPaginator(s.Client.SageMaker, &sagemaker.ListModelsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range page.Models {
+			name := aws.ToString(summary.ModelName)
+			m, err := s.Client.SageMaker.DescribeModel(ctx, &sagemaker.DescribeModelInput{ModelName: summary.ModelName})
+			if err != nil {
+				log.Printf("WARNING: Failed to describe model %s: %v", name, err)
+				continue
+			}
+
+			containers := m.Containers
+			if m.PrimaryContainer != nil {
+				containers = append([]smtypes.ContainerDefinition{*m.PrimaryContainer}, containers...)
+			}
+
+			for _, c := range containers {
+				uri := modelDataURI(c)
+				bucket, ok := s3Bucket(uri)
+				if !ok {
+					continue
+				}
+
+				result, seen := postures[bucket]
+				if !seen {
+					result.posture, result.err = s.bucketPosture(ctx, bucket)
+					postures[bucket] = result
+				}
+
+				f := models.Finding{
+					InstanceID: name,
+					Region:     target.Region,
+					PublicIP:   "N/A",
+					PrivateIP:  "N/A",
+					NameTag:    name,
+				}
+				switch {
+				case result.err != nil:
+					// Buckets in other accounts, or ones we can't read,
+					// may be just as open; say so rather than skip them.
+					f.Risk = models.RiskMedium
+					f.Service = "SageMaker Model From Unverifiable S3"
+					f.Description = "Model artifacts are loaded from an external bucket or one whose access can't be checked"
+					f.Evidence = fmt.Sprintf("Model %s loads %s (bucket %s: %v)", name, uri, bucket, result.err)
+				case result.posture.Public:
+					f.Risk = models.RiskHigh
+					f.Service = "SageMaker Model From Public S3"
+					f.Description = "Model artifacts are loaded from a publicly accessible bucket"
+					f.Evidence = fmt.Sprintf("Model %s loads %s (bucket %s is public)", name, uri, bucket)
+				default:
+					continue
+				}
+
+				findings = append(findings, f)
+				break
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func modelDataURI(c smtypes.ContainerDefinition) string {
+	if c.ModelDataUrl != nil {
+		return *c.ModelDataUrl
+	}
+	if c.ModelDataSource != nil && c.ModelDataSource.S3DataSource != nil {
+		return aws.ToString(c.ModelDataSource.S3DataSource.S3Uri)
+	}
+	return ""
+}
+
+// s3Bucket returns the bucket name from an s3:// URI.
+func s3Bucket(uri string) (string, bool) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", false
+	}
+	bucket, _, _ := strings.Cut(rest, "/")
+	return bucket, bucket != ""
+}
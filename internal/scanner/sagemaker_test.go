@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+)
+
+func TestScanSageMaker(t *testing.T) {
+	sm := &fake.SageMaker{
+		Notebooks: map[string]*sagemaker.DescribeNotebookInstanceOutput{
+			"shadow-nb": {
+				NotebookInstanceName: aws.String("shadow-nb"),
+				DirectInternetAccess: smtypes.DirectInternetAccessEnabled,
+				RootAccess:           smtypes.RootAccessEnabled,
+			},
+			"locked-nb": {
+				NotebookInstanceName: aws.String("locked-nb"),
+				DirectInternetAccess: smtypes.DirectInternetAccessDisabled,
+				RootAccess:           smtypes.RootAccessDisabled,
+				KmsKeyId:             aws.String("arn:aws:kms:us-east-1:111111111111:key/abc"),
+			},
+		},
+		Endpoints: map[string]*sagemaker.DescribeEndpointOutput{
+			"no-capture": {EndpointName: aws.String("no-capture"), EndpointConfigName: aws.String("cfg-a")},
+			"capture":    {EndpointName: aws.String("capture"), EndpointConfigName: aws.String("cfg-b")},
+		},
+		EndpointConfigs: map[string]*sagemaker.DescribeEndpointConfigOutput{
+			"cfg-a": {},
+			"cfg-b": {DataCaptureConfig: &smtypes.DataCaptureConfig{EnableCapture: aws.Bool(true), DestinationS3Uri: aws.String("s3://capture/")}},
+		},
+		Domains: map[string]*sagemaker.DescribeDomainOutput{
+			"d-public":  {DomainName: aws.String("research"), AppNetworkAccessType: smtypes.AppNetworkAccessTypePublicInternetOnly},
+			"d-private": {DomainName: aws.String("prod"), AppNetworkAccessType: smtypes.AppNetworkAccessTypeVpcOnly},
+		},
+		TrainingJobs: map[string]*sagemaker.DescribeTrainingJobOutput{
+			"open-job":     {CreationTime: aws.Time(time.Now().Add(-time.Hour))},
+			"isolated-job": {CreationTime: aws.Time(time.Now().Add(-time.Hour)), EnableNetworkIsolation: aws.Bool(true)},
+			"old-job":      {CreationTime: aws.Time(time.Now().Add(-365 * 24 * time.Hour))},
+		},
+		Models: map[string]*sagemaker.DescribeModelOutput{
+			"public-model":  {PrimaryContainer: &smtypes.ContainerDefinition{ModelDataUrl: aws.String("s3://open-weights/llama/model.tar.gz")}},
+			"private-model": {PrimaryContainer: &smtypes.ContainerDefinition{ModelDataUrl: aws.String("s3://team-weights/model.tar.gz")}},
+			"foreign-model": {PrimaryContainer: &smtypes.ContainerDefinition{ModelDataUrl: aws.String("s3://jumpstart-cache/model.tar.gz")}},
+		},
+	}
+	s3 := &fake.S3{Buckets: map[string]*fake.Bucket{
+		"open-weights": {Region: "us-east-1", Grants: []s3types.Grant{{Grantee: &s3types.Grantee{URI: aws.String("http://acs.amazonaws.com/groups/global/AllUsers")}}}},
+		"team-weights": {Region: "us-east-1", Encrypted: true},
+	}}
+
+	scn := newTestScanner(nil, nil, s3)
+	scn.Client.SageMaker = sm
+
+	findings, err := scn.ScanSageMaker(context.Background(), &Target{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("ScanSageMaker: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key]models.RiskLevel{}
+	for _, f := range findings {
+		got[key{f.InstanceID, f.Service}] = f.Risk
+	}
+
+	want := map[key]models.RiskLevel{
+		{"shadow-nb", "SageMaker Notebook Internet Access"}:  models.RiskHigh,
+		{"shadow-nb", "SageMaker Notebook Root Access"}:      models.RiskMedium,
+		{"shadow-nb", "SageMaker Notebook Encryption"}:       models.RiskMedium,
+		{"no-capture", "SageMaker Endpoint Data Capture"}:    models.RiskLow,
+		{"capture", "SageMaker Endpoint Data Capture"}:       models.RiskMedium,
+		{"d-public", "SageMaker Studio Public Internet"}:     models.RiskHigh,
+		{"open-job", "SageMaker Training Network Isolation"}: models.RiskMedium,
+		{"public-model", "SageMaker Model From Public S3"}:   models.RiskHigh,
+		// jumpstart-cache isn't in the account, so its access can't be read.
+		{"foreign-model", "SageMaker Model From Unverifiable S3"}: models.RiskMedium,
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for k, risk := range want {
+		if got[k] != risk {
+			t.Errorf("%s / %s: risk = %q, want %s", k.resource, k.service, got[k], risk)
+		}
+	}
+}
+
+func TestS3Bucket(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+		ok   bool
+	}{
+		{"s3://models/llama/model.tar.gz", "models", true},
+		{"s3://models", "models", true},
+		{"https://example.com/model.tar.gz", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := s3Bucket(tt.uri)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("s3Bucket(%q) = %q, %v; want %q, %v", tt.uri, got, ok, tt.want, tt.ok)
+		}
+	}
+}
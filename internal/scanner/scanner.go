@@ -3,10 +3,13 @@ package scanner
 import (
 	"context"
 	"fmt"
-	"log"
+	"math"
+	"net/netip"
+	"strings"
 
 	client "github.com/K0NGR3SS/ghostweights/internal/aws"
 	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/probe"
 	"github.com/K0NGR3SS/ghostweights/internal/ui"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -14,33 +17,49 @@ import (
 	"github.com/pterm/pterm"
 )
 
-var aiPorts = map[int32]string{
-	11434: "Ollama API",
-	8501:  "Streamlit App",
-	7860:  "Gradio (HuggingFace)",
-	8000:  "vLLM / FastChat",
-	8265:  "Ray Dashboard",
-	8888:  "Jupyter Notebook",
-	5000:  "MLflow / Flask",
-}
-
 type Scanner struct {
-	Client  *client.Client
-	Deep    bool
-	sgCache map[string][]types.IpPermission
+	Client *client.Client
+	Deep   bool
+	S3     bool
+	Ports  []AIPort
+
+	// Prober, when set, fingerprints exposed ports over HTTP.
+	Prober *probe.Prober
+
+	// Kubeconfig, when set, is a kubeconfig path whose contexts are matched
+	// to EKS clusters so their Deployments and Services are checked.
+	Kubeconfig string
+
+	// Detectors run on every scan. New populates it from the registry.
+	Detectors []Detector
+
+	// ExcludeIDs and ExcludeTags remove instances before any analysis, so
+	// excluded hosts are never queried or sent SSM commands.
+	ExcludeIDs  []string
+	ExcludeTags []TagFilter
+
+	sgCache     map[string][]types.IpPermission
+	sgOwners    map[string]string
+	prefixLists map[string][]string
 }
 
 func New(c *client.Client, deep bool) *Scanner {
 	return &Scanner{
-		Client:  c,
-		Deep:    deep,
-		sgCache: make(map[string][]types.IpPermission),
+		Client:      c,
+		Deep:        deep,
+		Ports:       DefaultAIPorts,
+		Detectors:   Detectors(),
+		sgCache:     make(map[string][]types.IpPermission),
+		sgOwners:    make(map[string]string),
+		prefixLists: make(map[string][]string),
 	}
 }
 
+// Scan runs the regional detectors against the region's running instances.
+// When some detectors fail it returns a *PartialError alongside the findings
+// of the rest; any other error means the region couldn't be scanned.
 func (s *Scanner) Scan(ctx context.Context, spinner *pterm.SpinnerPrinter) ([]models.Finding, error) {
-	var findings []models.Finding
-	var allInstances []types.Instance
+	var allInstances, excluded []types.Instance
 
 	ui.UpdateSpinner(spinner, fmt.Sprintf("Fetching EC2 instances in %s (with pagination)...", s.Client.Region))
 
@@ -62,108 +81,42 @@ func (s *Scanner) Scan(ctx context.Context, spinner *pterm.SpinnerPrinter) ([]mo
 		pageCount++
 
 		for _, reservation := range page.Reservations {
-			allInstances = append(allInstances, reservation.Instances...)
-		}
-	}
-
-	ui.UpdateSpinner(spinner, fmt.Sprintf("Found %d running instances across %d pages", len(allInstances), pageCount))
-
-	seen := map[string]struct{}{}
-
-	for idx, instance := range allInstances {
-		instanceID := aws.ToString(instance.InstanceId)
-		if instanceID == "" {
-			continue
-		}
-
-		ui.UpdateSpinner(spinner, fmt.Sprintf("Scanning %s (%d/%d)...", instanceID, idx+1, len(allInstances)))
-
-		name := getNameTag(instance.Tags)
-		publicIP := getPublicIP(instance)
-		privateIP := getPrivateIP(instance)
-
-		if instance.MetadataOptions != nil && instance.MetadataOptions.HttpTokens == types.HttpTokensStateOptional {
-			findings = append(findings, models.Finding{
-				InstanceID:  instanceID,
-				Region:      s.Client.Region,
-				PublicIP:    publicIP,
-				PrivateIP:   privateIP,
-				NameTag:     name,
-				Risk:        models.RiskHigh,
-				Service:     "IMDSv1 Enabled",
-				Description: "Instance allows IMDSv1 (SSRF vulnerable)",
-				Evidence:    "HttpTokens=optional allows unauthenticated metadata access",
-			})
-		}
-
-		for _, sg := range instance.SecurityGroups {
-			groupID := aws.ToString(sg.GroupId)
-			if groupID == "" {
-				continue
-			}
-
-			sgRules, err := s.getSecurityGroupRulesCached(ctx, groupID)
-			if err != nil {
-				log.Printf("WARNING: Failed to get rules for SG %s: %v", groupID, err)
-				continue
-			}
-
-			for _, rule := range sgRules {
-				if !isPubliclyExposed(rule) {
+			for _, instance := range reservation.Instances {
+				if s.isExcluded(instance) {
+					excluded = append(excluded, instance)
 					continue
 				}
-				if !isTCPOrAll(rule) {
-					continue
-				}
-
-				for port, serviceName := range aiPorts {
-					if ruleCoversPort(rule, port) {
-						key := fmt.Sprintf("%s:%d:%s", instanceID, port, serviceName)
-						if _, ok := seen[key]; ok {
-							continue
-						}
-						seen[key] = struct{}{}
-
-						findings = append(findings, models.Finding{
-							InstanceID:  instanceID,
-							Region:      s.Client.Region,
-							PublicIP:    publicIP,
-							PrivateIP:   privateIP,
-							NameTag:     name,
-							Risk:        models.RiskCritical,
-							Service:     serviceName,
-							Port:        port,
-							Description: fmt.Sprintf("Exposed %s port", serviceName),
-							Evidence:    fmt.Sprintf("Port %d open to 0.0.0.0/0 or ::/0 in SG %s", port, groupID),
-						})
-					}
-				}
+				allInstances = append(allInstances, instance)
 			}
 		}
 	}
 
-	if s.Deep {
-		var allInstanceIDs []string
-		for _, i := range allInstances {
-			if i.InstanceId != nil {
-				allInstanceIDs = append(allInstanceIDs, *i.InstanceId)
-			}
-		}
+	ui.UpdateSpinner(spinner, fmt.Sprintf("Found %d running instances across %d pages (%d excluded)", len(allInstances), pageCount, len(excluded)))
 
-		if len(allInstanceIDs) > 0 {
-			ssmFindings, err := s.DeepScan(ctx, allInstanceIDs, spinner)
-			if err != nil {
-				log.Printf("WARNING: SSM Deep Scan encountered errors: %v", err)
-				ui.UpdateSpinner(spinner, fmt.Sprintf("SSM Scan completed with errors: %v", err))
-			}
-			findings = append(findings, ssmFindings...)
-		}
+	target := &Target{
+		Region:    s.Client.Region,
+		Instances: allInstances,
+		Excluded:  excluded,
+		Spinner:   spinner,
+	}
+
+	return s.runDetectors(ctx, ScopeRegional, target)
+}
+
+// ScanGlobal runs the account-wide detectors, such as the S3 bucket scan.
+// It should be called once per account rather than once per region. Like
+// Scan, it returns a *PartialError alongside the findings of the detectors
+// that succeeded when others fail.
+func (s *Scanner) ScanGlobal(ctx context.Context, spinner *pterm.SpinnerPrinter) ([]models.Finding, error) {
+	target := &Target{
+		Region:  s.Client.Region,
+		Spinner: spinner,
 	}
 
-	return findings, nil
+	return s.runDetectors(ctx, ScopeGlobal, target)
 }
 
-//Add caching to avoid duplicate SG queries
+// Add caching to avoid duplicate SG queries
 func (s *Scanner) getSecurityGroupRulesCached(ctx context.Context, groupID string) ([]types.IpPermission, error) {
 	// Check cache first
 	if rules, ok := s.sgCache[groupID]; ok {
@@ -182,21 +135,192 @@ func (s *Scanner) getSecurityGroupRulesCached(ctx context.Context, groupID strin
 
 	rules := res.SecurityGroups[0].IpPermissions
 	s.sgCache[groupID] = rules
+	s.sgOwners[groupID] = aws.ToString(res.SecurityGroups[0].OwnerId)
 	return rules, nil
 }
 
-func isPubliclyExposed(rule types.IpPermission) bool {
-	for _, ipRange := range rule.IpRanges {
-		if aws.ToString(ipRange.CidrIp) == "0.0.0.0/0" {
-			return true
+// prefixListCIDRsCached returns the CIDRs in a managed prefix list.
+func (s *Scanner) prefixListCIDRsCached(ctx context.Context, id string) ([]string, error) {
+	if cidrs, ok := s.prefixLists[id]; ok {
+		return cidrs, nil
+	}
+
+	var cidrs []string
+	paginator := ec2.NewGetManagedPrefixListEntriesPaginator(s.Client.EC2, &ec2.GetManagedPrefixListEntriesInput{
+		PrefixListId: aws.String(id),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page.Entries {
+			cidrs = append(cidrs, aws.ToString(e.Cidr))
+		}
+	}
+
+	s.prefixLists[id] = cidrs
+	return cidrs, nil
+}
+
+// exposure describes who a single security group rule lets in.
+type exposure struct {
+	// Cap is the highest risk the rule's width justifies; empty when the
+	// rule isn't exposed at all.
+	Cap models.RiskLevel
+	// Source is the CIDR, prefix list or group reference responsible.
+	Source string
+	// World is true when the rule covers the whole address space of a
+	// family, V4 and V6 say which internet families are open.
+	World  bool
+	V4, V6 bool
+}
+
+// internet reports whether the exposure is to public address space, as
+// opposed to a referenced security group.
+func (e exposure) internet() bool {
+	return e.V4 || e.V6
+}
+
+// ruleExposure scores how wide a rule's sources are and returns the widest:
+//
+//   - all of IPv4 or IPv6, even when split across CIDRs such as 0.0.0.0/1
+//     and 128.0.0.0/1: no cap
+//   - public space at least a /8 (IPv4) or /16 (IPv6): HIGH
+//   - public space at least a /16 (IPv4) or /32 (IPv6): MEDIUM
+//   - managed prefix lists, scored like their entries; MEDIUM when the
+//     entries can't be read
+//   - security groups in other accounts or across VPC peering: MEDIUM
+//
+// owner is the account that owns the rule's group.
+func (s *Scanner) ruleExposure(ctx context.Context, rule types.IpPermission, owner string) exposure {
+	var candidates []exposure
+
+	var v4, v6 []string
+	for _, r := range rule.IpRanges {
+		v4 = append(v4, aws.ToString(r.CidrIp))
+	}
+	for _, r := range rule.Ipv6Ranges {
+		v6 = append(v6, aws.ToString(r.CidrIpv6))
+	}
+	candidates = append(candidates, cidrExposure(v4), cidrExposure(v6))
+
+	for _, pl := range rule.PrefixListIds {
+		id := aws.ToString(pl.PrefixListId)
+		cidrs, err := s.prefixListCIDRsCached(ctx, id)
+		if err != nil {
+			candidates = append(candidates, exposure{Cap: models.RiskMedium, Source: fmt.Sprintf("prefix list %s (entries unavailable)", id)})
+			continue
+		}
+		e := cidrExposure(cidrs)
+		if e.Cap != "" {
+			e.Source = fmt.Sprintf("prefix list %s (%s)", id, e.Source)
+		}
+		candidates = append(candidates, e)
+	}
+
+	for _, pair := range rule.UserIdGroupPairs {
+		group := aws.ToString(pair.GroupId)
+		switch {
+		case pair.VpcPeeringConnectionId != nil:
+			candidates = append(candidates, exposure{Cap: models.RiskMedium, Source: fmt.Sprintf("SG %s in account %s via %s", group, aws.ToString(pair.UserId), *pair.VpcPeeringConnectionId)})
+		case pair.UserId != nil && owner != "" && *pair.UserId != owner:
+			candidates = append(candidates, exposure{Cap: models.RiskMedium, Source: fmt.Sprintf("SG %s in account %s", group, *pair.UserId)})
 		}
 	}
-	for _, ip6Range := range rule.Ipv6Ranges {
-		if aws.ToString(ip6Range.CidrIpv6) == "::/0" {
-			return true
+
+	// Candidates of equal width add up: a rule holding both 0.0.0.0/0 and
+	// ::/0 is open to both families.
+	var widest exposure
+	for _, c := range candidates {
+		switch {
+		case c.Cap == "":
+		case c.Cap.Rank() > widest.Cap.Rank():
+			widest = c
+		case c.Cap == widest.Cap:
+			widest.Source += " + " + c.Source
+			widest.World = widest.World || c.World
+			widest.V4 = widest.V4 || c.V4
+			widest.V6 = widest.V6 || c.V6
 		}
 	}
-	return false
+	return widest
+}
+
+// cidrExposure scores a set of CIDRs from one family. CIDR blocks either
+// nest or are disjoint, so the covered fraction of the address space is the
+// sum over the blocks that aren't inside another one. Private and other
+// non-internet ranges are ignored.
+func cidrExposure(cidrs []string) exposure {
+	var prefixes []netip.Prefix
+	for _, c := range cidrs {
+		p, err := netip.ParsePrefix(c)
+		if err != nil || !isPublicPrefix(c) {
+			continue
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	if len(prefixes) == 0 {
+		return exposure{}
+	}
+
+	var top []netip.Prefix
+	for i, p := range prefixes {
+		nested := false
+		for j, q := range prefixes {
+			if i != j && q.Bits() <= p.Bits() && q.Contains(p.Addr()) && (q.Bits() < p.Bits() || j < i) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			top = append(top, p)
+		}
+	}
+
+	coverage := 0.0
+	sources := make([]string, 0, len(top))
+	for _, p := range top {
+		coverage += math.Ldexp(1, -p.Bits())
+		sources = append(sources, p.String())
+	}
+
+	is6 := top[0].Addr().Is6()
+	broad, wide := 8, 16
+	if is6 {
+		broad, wide = 16, 32
+	}
+
+	e := exposure{Source: strings.Join(sources, " + "), V4: !is6, V6: is6}
+	switch {
+	case coverage >= 1:
+		e.Cap, e.World = models.RiskCritical, true
+	case coverage >= math.Ldexp(1, -broad):
+		e.Cap = models.RiskHigh
+	case coverage >= math.Ldexp(1, -wide):
+		e.Cap = models.RiskMedium
+	default:
+		return exposure{}
+	}
+	return e
+}
+
+// mixedCIDRExposure scores CIDRs that may mix IPv4 and IPv6, returning the
+// wider of the two families.
+func mixedCIDRExposure(cidrs []string) exposure {
+	var v4, v6 []string
+	for _, c := range cidrs {
+		if strings.Contains(c, ":") {
+			v6 = append(v6, c)
+		} else {
+			v4 = append(v4, c)
+		}
+	}
+	e4, e6 := cidrExposure(v4), cidrExposure(v6)
+	if e6.Cap.Rank() > e4.Cap.Rank() {
+		return e6
+	}
+	return e4
 }
 
 func isTCPOrAll(rule types.IpPermission) bool {
@@ -232,4 +356,4 @@ func getPrivateIP(instance types.Instance) string {
 		return *instance.PrivateIpAddress
 	}
 	return "N/A"
-}
\ No newline at end of file
+}
@@ -0,0 +1,420 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	client "github.com/K0NGR3SS/ghostweights/internal/aws"
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/probe"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func newTestScanner(ec2 *fake.EC2, ssm *fake.SSM, s3 *fake.S3) *Scanner {
+	c := &client.Client{Region: "us-east-1"}
+	if ec2 != nil {
+		c.EC2 = ec2
+	}
+	if ssm != nil {
+		c.SSM = ssm
+	}
+	if s3 != nil {
+		c.S3 = s3
+	}
+	return New(c, false)
+}
+
+func testInstance(id string, groupIDs ...string) types.Instance {
+	inst := types.Instance{
+		InstanceId:       aws.String(id),
+		PublicIpAddress:  aws.String("203.0.113.10"),
+		PrivateIpAddress: aws.String("10.0.0.10"),
+		Ipv6Address:      aws.String("2001:db8::10"),
+		State:            &types.InstanceState{Name: types.InstanceStateNameRunning},
+		MetadataOptions:  &types.InstanceMetadataOptionsResponse{HttpTokens: types.HttpTokensStateRequired},
+	}
+	for _, g := range groupIDs {
+		inst.SecurityGroups = append(inst.SecurityGroups, types.GroupIdentifier{GroupId: aws.String(g)})
+	}
+	return inst
+}
+
+func tcpRule(from, to int32, cidrs ...string) types.IpPermission {
+	rule := types.IpPermission{
+		IpProtocol: aws.String("tcp"),
+		FromPort:   aws.Int32(from),
+		ToPort:     aws.Int32(to),
+	}
+	for _, c := range cidrs {
+		rule.IpRanges = append(rule.IpRanges, types.IpRange{CidrIp: aws.String(c)})
+	}
+	return rule
+}
+
+func findingsByService(findings []models.Finding) map[string]models.Finding {
+	m := map[string]models.Finding{}
+	for _, f := range findings {
+		m[f.Service] = f
+	}
+	return m
+}
+
+func TestScanSecurityGroupExposure(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []types.IpPermission
+		expected map[string]models.RiskLevel
+	}{
+		{
+			name:     "ollama open to world",
+			rules:    []types.IpPermission{tcpRule(11434, 11434, "0.0.0.0/0")},
+			expected: map[string]models.RiskLevel{"Ollama API": models.RiskCritical},
+		},
+		{
+			name:     "ipv6 world open",
+			rules:    []types.IpPermission{{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(8501), ToPort: aws.Int32(8501), Ipv6Ranges: []types.Ipv6Range{{CidrIpv6: aws.String("::/0")}}}},
+			expected: map[string]models.RiskLevel{"Streamlit App": models.RiskHigh},
+		},
+		{
+			name:     "restricted cidr",
+			rules:    []types.IpPermission{tcpRule(11434, 11434, "10.0.0.0/8")},
+			expected: map[string]models.RiskLevel{},
+		},
+		{
+			name:  "port range covers several services",
+			rules: []types.IpPermission{tcpRule(8000, 8900, "0.0.0.0/0")},
+			expected: map[string]models.RiskLevel{
+				"vLLM / FastChat":  models.RiskHigh,
+				"Ray Dashboard":    models.RiskCritical,
+				"Streamlit App":    models.RiskHigh,
+				"Jupyter Notebook": models.RiskCritical,
+			},
+		},
+		{
+			name:     "world split into halves",
+			rules:    []types.IpPermission{tcpRule(11434, 11434, "0.0.0.0/1", "128.0.0.0/1")},
+			expected: map[string]models.RiskLevel{"Ollama API": models.RiskCritical},
+		},
+		{
+			name:     "public /8 is broad",
+			rules:    []types.IpPermission{tcpRule(11434, 11434, "44.0.0.0/8")},
+			expected: map[string]models.RiskLevel{"Ollama API": models.RiskHigh},
+		},
+		{
+			name:     "public /16 is wide",
+			rules:    []types.IpPermission{tcpRule(11434, 11434, "52.95.0.0/16")},
+			expected: map[string]models.RiskLevel{"Ollama API": models.RiskMedium},
+		},
+		{
+			name:     "public /24 is narrow",
+			rules:    []types.IpPermission{tcpRule(11434, 11434, "198.51.100.0/24")},
+			expected: map[string]models.RiskLevel{},
+		},
+		{
+			name:     "private /8 is ignored",
+			rules:    []types.IpPermission{tcpRule(11434, 11434, "10.0.0.0/8")},
+			expected: map[string]models.RiskLevel{},
+		},
+		{
+			name:     "udp is ignored",
+			rules:    []types.IpPermission{{IpProtocol: aws.String("udp"), FromPort: aws.Int32(11434), ToPort: aws.Int32(11434), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}},
+			expected: map[string]models.RiskLevel{},
+		},
+		{
+			name:  "all traffic",
+			rules: []types.IpPermission{{IpProtocol: aws.String("-1"), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}},
+			expected: map[string]models.RiskLevel{
+				"MLflow / Flask":       models.RiskHigh,
+				"Gradio (HuggingFace)": models.RiskHigh,
+				"vLLM / FastChat":      models.RiskHigh,
+				"Ray Dashboard":        models.RiskCritical,
+				"Streamlit App":        models.RiskHigh,
+				"Jupyter Notebook":     models.RiskCritical,
+				"Ollama API":           models.RiskCritical,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ec2 := &fake.EC2{
+				Instances:      []types.Instance{testInstance("i-1", "sg-1")},
+				SecurityGroups: []types.SecurityGroup{{GroupId: aws.String("sg-1"), IpPermissions: tt.rules}},
+			}
+			scn := newTestScanner(ec2, nil, nil)
+
+			findings, err := scn.Scan(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+
+			got := findingsByService(findings)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("got %d findings %v, want %d", len(got), got, len(tt.expected))
+			}
+			for service, risk := range tt.expected {
+				f, ok := got[service]
+				if !ok {
+					t.Errorf("missing finding for %s", service)
+					continue
+				}
+				if f.Risk != risk {
+					t.Errorf("%s: risk = %s, want %s", service, f.Risk, risk)
+				}
+				if f.InstanceID != "i-1" || f.Region != "us-east-1" {
+					t.Errorf("%s: unexpected attribution %s/%s", service, f.InstanceID, f.Region)
+				}
+			}
+		})
+	}
+}
+
+func TestScanCustomPorts(t *testing.T) {
+	ec2 := &fake.EC2{
+		Instances: []types.Instance{testInstance("i-1", "sg-1")},
+		SecurityGroups: []types.SecurityGroup{{
+			GroupId:       aws.String("sg-1"),
+			IpPermissions: []types.IpPermission{tcpRule(3000, 3000, "0.0.0.0/0"), tcpRule(9000, 9000, "0.0.0.0/0"), tcpRule(11434, 11434, "0.0.0.0/0")},
+		}},
+	}
+	scn := newTestScanner(ec2, nil, nil)
+	scn.Ports = MergePorts(DefaultAIPorts, []AIPort{
+		{Port: 3000, Name: "Open WebUI", Risk: models.RiskMedium},
+		{Port: 9000},
+		{Port: 11434, Risk: models.RiskLow},
+	})
+
+	findings, err := scn.Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	got := findingsByService(findings)
+	if got["Open WebUI"].Risk != models.RiskMedium {
+		t.Errorf("Open WebUI risk = %q, want MEDIUM", got["Open WebUI"].Risk)
+	}
+	if got["Ollama API"].Risk != models.RiskLow {
+		t.Errorf("overridden Ollama risk = %q, want LOW", got["Ollama API"].Risk)
+	}
+	if f := got["port 9000"]; f.Risk != models.RiskHigh || f.Description != "Exposed custom port 9000" {
+		t.Errorf("unnamed custom port = %q %q, want HIGH with a default name", f.Risk, f.Description)
+	}
+}
+
+func TestScanIMDSv1(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens types.HttpTokensState
+		want   bool
+	}{
+		{name: "optional tokens", tokens: types.HttpTokensStateOptional, want: true},
+		{name: "required tokens", tokens: types.HttpTokensStateRequired, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := testInstance("i-1")
+			inst.MetadataOptions.HttpTokens = tt.tokens
+			scn := newTestScanner(&fake.EC2{Instances: []types.Instance{inst}}, nil, nil)
+
+			findings, err := scn.Scan(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+
+			_, got := findingsByService(findings)["IMDSv1 Enabled"]
+			if got != tt.want {
+				t.Errorf("IMDSv1 finding = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanExclusions(t *testing.T) {
+	tagged := testInstance("i-tagged", "sg-1")
+	tagged.Tags = []types.Tag{{Key: aws.String("Environment"), Value: aws.String("sandbox-approved")}}
+	ignored := testInstance("i-ignored", "sg-1")
+	ignored.Tags = []types.Tag{{Key: aws.String("ghostweights:ignore"), Value: aws.String("true")}}
+
+	ec2 := &fake.EC2{
+		Instances: []types.Instance{testInstance("i-keep", "sg-1"), testInstance("i-byid", "sg-1"), tagged, ignored},
+		SecurityGroups: []types.SecurityGroup{{
+			GroupId:       aws.String("sg-1"),
+			IpPermissions: []types.IpPermission{tcpRule(11434, 11434, "0.0.0.0/0")},
+		}},
+	}
+	scn := newTestScanner(ec2, nil, nil)
+	scn.ExcludeIDs = []string{"i-byid"}
+	scn.ExcludeTags = []TagFilter{ParseTagFilter("ghostweights:ignore"), ParseTagFilter("Environment=sandbox-*")}
+
+	findings, err := scn.Scan(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	for _, f := range findings {
+		if f.InstanceID != "i-keep" {
+			t.Errorf("finding for excluded instance %s", f.InstanceID)
+		}
+	}
+	if len(findings) == 0 {
+		t.Error("expected findings for i-keep")
+	}
+}
+
+func TestApplyProbeResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *probe.Result
+		wantRisk models.RiskLevel
+	}{
+		{"confirmed", &probe.Result{Reachable: true, Service: "Ollama", Models: []string{"llama3"}}, models.RiskCritical},
+		{"auth required", &probe.Result{Reachable: true, Service: "Jupyter", Auth: probe.AuthRequired}, models.RiskMedium},
+		{"unrecognised", &probe.Result{Reachable: true, Scheme: "http"}, models.RiskMedium},
+		{"no response", &probe.Result{}, models.RiskMedium},
+		{"timed out", &probe.Result{Err: fmt.Errorf("GET: %w", context.DeadlineExceeded)}, models.RiskCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := models.Finding{Risk: models.RiskCritical, Port: 11434, Description: "Exposed Ollama API"}
+			applyProbeResult(&f, tt.result)
+			if f.Risk != tt.wantRisk {
+				t.Errorf("risk = %s, want %s", f.Risk, tt.wantRisk)
+			}
+		})
+	}
+}
+
+func TestExposureFindings(t *testing.T) {
+	port := models.Finding{InstanceID: "i-1", PublicIP: "203.0.113.7", Port: 8265, Service: "Ray", Risk: models.RiskCritical}
+	result := &probe.Result{
+		Reachable: true,
+		Service:   "Ray",
+		Scheme:    "http",
+		Auth:      probe.AuthNone,
+		Exposures: []probe.Exposure{{Title: "Unauthenticated Ray Job API (RCE)", Impact: probe.ImpactExec, Evidence: "GET /api/jobs/ returned 200 without credentials"}},
+	}
+
+	got := exposureFindings(port, result)
+	if len(got) != 1 {
+		t.Fatalf("got %d findings, want 1", len(got))
+	}
+	if got[0].Service != "Unauthenticated Ray Job API (RCE)" || got[0].Risk != models.RiskCritical || got[0].InstanceID != "i-1" {
+		t.Errorf("unexpected finding %+v", got[0])
+	}
+	if port.Service != "Ray" {
+		t.Errorf("port finding was modified: %+v", port)
+	}
+}
+
+func TestRuleExposureSources(t *testing.T) {
+	ec2 := &fake.EC2{PrefixLists: map[string][]string{
+		"pl-world":  {"0.0.0.0/1", "128.0.0.0/1"},
+		"pl-office": {"198.51.100.0/24"},
+	}}
+	scn := newTestScanner(ec2, nil, nil)
+
+	tests := []struct {
+		name       string
+		rule       types.IpPermission
+		wantCap    models.RiskLevel
+		wantSource string
+		wantV6     bool
+	}{
+		{
+			name:       "widest source wins",
+			rule:       tcpRule(0, 65535, "198.51.100.0/24", "44.0.0.0/8", "0.0.0.0/0"),
+			wantCap:    models.RiskCritical,
+			wantSource: "0.0.0.0/0",
+		},
+		{
+			name: "IPv4 and IPv6 world in one rule",
+			rule: types.IpPermission{
+				IpRanges:   []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+				Ipv6Ranges: []types.Ipv6Range{{CidrIpv6: aws.String("::/0")}},
+			},
+			wantCap:    models.RiskCritical,
+			wantSource: "0.0.0.0/0 + ::/0",
+			wantV6:     true,
+		},
+		{
+			name:       "prefix list covering the world",
+			rule:       types.IpPermission{PrefixListIds: []types.PrefixListId{{PrefixListId: aws.String("pl-world")}}},
+			wantCap:    models.RiskCritical,
+			wantSource: "prefix list pl-world (0.0.0.0/1 + 128.0.0.0/1)",
+		},
+		{
+			name: "narrow prefix list",
+			rule: types.IpPermission{PrefixListIds: []types.PrefixListId{{PrefixListId: aws.String("pl-office")}}},
+		},
+		{
+			name:       "unreadable prefix list",
+			rule:       types.IpPermission{PrefixListIds: []types.PrefixListId{{PrefixListId: aws.String("pl-missing")}}},
+			wantCap:    models.RiskMedium,
+			wantSource: "prefix list pl-missing (entries unavailable)",
+		},
+		{
+			name:       "cross-account group",
+			rule:       types.IpPermission{UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-other"), UserId: aws.String("222222222222")}}},
+			wantCap:    models.RiskMedium,
+			wantSource: "SG sg-other in account 222222222222",
+		},
+		{
+			name:       "peered group",
+			rule:       types.IpPermission{UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-peer"), UserId: aws.String("111111111111"), VpcPeeringConnectionId: aws.String("pcx-1")}}},
+			wantCap:    models.RiskMedium,
+			wantSource: "SG sg-peer in account 111111111111 via pcx-1",
+		},
+		{
+			name: "same-account group",
+			rule: types.IpPermission{UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-app"), UserId: aws.String("111111111111")}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scn.ruleExposure(context.Background(), tt.rule, "111111111111")
+			if got.Cap != tt.wantCap || got.Source != tt.wantSource {
+				t.Errorf("got %s %q, want %s %q", got.Cap, got.Source, tt.wantCap, tt.wantSource)
+			}
+			if got.V6 != tt.wantV6 {
+				t.Errorf("V6 = %v, want %v", got.V6, tt.wantV6)
+			}
+		})
+	}
+}
+
+// failingDetector always fails, like a check denied by IAM.
+type failingDetector struct{}
+
+func (failingDetector) Info() DetectorInfo {
+	return DetectorInfo{ID: "failing", Scope: ScopeRegional}
+}
+
+func (failingDetector) Enabled(s *Scanner) bool { return true }
+
+func (failingDetector) Detect(ctx context.Context, s *Scanner, target *Target) ([]models.Finding, error) {
+	return nil, errors.New("AccessDenied")
+}
+
+func TestScanReportsFailedDetectors(t *testing.T) {
+	inst := testInstance("i-1")
+	inst.MetadataOptions.HttpTokens = types.HttpTokensStateOptional
+	scn := newTestScanner(&fake.EC2{Instances: []types.Instance{inst}}, nil, nil)
+	scn.Detectors = []Detector{imdsDetector{}, failingDetector{}}
+
+	findings, err := scn.Scan(context.Background(), nil)
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("err = %v, want *PartialError", err)
+	}
+	if len(findings) != 1 || findings[0].Service != "IMDSv1 Enabled" {
+		t.Errorf("findings = %v, want the IMDSv1 finding kept", findings)
+	}
+}
@@ -0,0 +1,217 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/K0NGR3SS/ghostweights/internal/ui"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// userDataWorkers bounds concurrent DescribeInstanceAttribute calls.
+const userDataWorkers = 8
+
+// ScanUserData reads the user data of each instance and flags scripts that
+// install or start AI runtimes or hold LLM provider keys. Unlike the deep
+// scan it needs no SSM agent, only ec2:DescribeInstanceAttribute.
+func (s *Scanner) ScanUserData(ctx context.Context, target *Target) ([]models.Finding, error) {
+	ui.UpdateSpinner(target.Spinner, fmt.Sprintf("Reading user data of %d instances...", len(target.Instances)))
+
+	scripts := make([]string, len(target.Instances))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(userDataWorkers, len(target.Instances)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				scripts[i] = s.instanceUserData(ctx, aws.ToString(target.Instances[i].InstanceId))
+			}
+		}()
+	}
+	for i := range target.Instances {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var findings []models.Finding
+	for i, instance := range target.Instances {
+		if scripts[i] == "" {
+			continue
+		}
+		base := models.Finding{
+			InstanceID: aws.ToString(instance.InstanceId),
+			Region:     target.Region,
+			PublicIP:   getPublicIP(instance),
+			PrivateIP:  getPrivateIP(instance),
+			NameTag:    getNameTag(instance.Tags),
+		}
+
+		if boot := matchAIBootstrap(scripts[i]); len(boot) > 0 {
+			f := base
+			f.Risk = models.RiskHigh
+			f.Service = "User Data AI Bootstrap"
+			f.Description = "User data installs or starts an AI runtime"
+			f.Evidence = bootstrapEvidence(boot)
+			findings = append(findings, f)
+		}
+		for _, key := range userDataAPIKeys(scripts[i]) {
+			f := base
+			f.Risk = models.RiskCritical
+			f.Service = "Exposed API Key"
+			f.Description = "API key found in instance user data"
+			f.Evidence = key
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+// instanceUserData returns the decoded user data of an instance, or "" when
+// it has none or it can't be read.
+func (s *Scanner) instanceUserData(ctx context.Context, instanceID string) string {
+	out, err := s.Client.EC2.DescribeInstanceAttribute(ctx, &ec2.DescribeInstanceAttributeInput{
+		InstanceId: aws.String(instanceID),
+		Attribute:  types.InstanceAttributeNameUserData,
+	})
+	if err != nil {
+		log.Printf("WARNING: Failed to read user data of %s: %v", instanceID, err)
+		return ""
+	}
+	if out.UserData == nil || aws.ToString(out.UserData.Value) == "" {
+		return ""
+	}
+	script, err := decodeUserData(*out.UserData.Value)
+	if err != nil {
+		log.Printf("WARNING: Failed to decode user data of %s: %v", instanceID, err)
+		return ""
+	}
+	return script
+}
+
+// maxUserDataBytes bounds decompressed user data. EC2 caps user data at
+// 16 KB, but gzip lets that expand a long way.
+const maxUserDataBytes = 1 << 20
+
+// decodeUserData turns user data as the EC2 and Auto Scaling APIs return it,
+// base64-encoded and optionally gzipped, into text.
+func decodeUserData(encoded string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", fmt.Errorf("invalid base64: %w", err)
+	}
+	if bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return "", err
+		}
+		defer zr.Close()
+		raw, err = io.ReadAll(io.LimitReader(zr, maxUserDataBytes))
+		if err != nil {
+			return "", fmt.Errorf("invalid gzip: %w", err)
+		}
+	}
+	return string(raw), nil
+}
+
+// aiBootstrap is an AI runtime that user data can install or start.
+type aiBootstrap struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+var aiBootstraps = []aiBootstrap{
+	{"Ollama", regexp.MustCompile(`(?i)ollama\.(ai|com)/install\.sh|\bollama\s+(serve|pull|run)\b|ollama/ollama`)},
+	{"vLLM", regexp.MustCompile(`(?i)\bpip3?\s+install\b.*\bvllm\b|\bvllm\s+serve\b|vllm\.entrypoints|vllm/vllm-openai`)},
+	{"Text Generation Inference", regexp.MustCompile(`(?i)huggingface/text-generation-inference|\btext-generation-launcher\b`)},
+	{"SGLang", regexp.MustCompile(`(?i)\bpip3?\s+install\b.*\bsglang\b|sglang\.launch_server|lmsysorg/sglang`)},
+	{"llama.cpp", regexp.MustCompile(`(?i)ggerganov/llama\.cpp|ggml-org/llama\.cpp|\bllama-server\b|\bpip3?\s+install\b.*\bllama-cpp-python\b`)},
+	{"LocalAI", regexp.MustCompile(`(?i)localai\.io/install\.sh|localai/localai|go-skynet/local-ai`)},
+	{"Open WebUI", regexp.MustCompile(`(?i)open-webui/open-webui|\bpip3?\s+install\b.*\bopen-webui\b`)},
+	{"Hugging Face download", regexp.MustCompile(`(?i)\b(huggingface-cli|hf)\s+download\b|snapshot_download\(`)},
+}
+
+// bootstrapMatch is the first line of a script that installs or starts an
+// AI runtime.
+type bootstrapMatch struct {
+	Name string
+	Line string
+}
+
+// matchAIBootstrap returns a match for each runtime in aiBootstraps that
+// script installs or starts, in catalogue order.
+func matchAIBootstrap(script string) []bootstrapMatch {
+	var matches []bootstrapMatch
+	for _, b := range aiBootstraps {
+		for _, line := range strings.Split(script, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "#") || !b.Pattern.MatchString(line) {
+				continue
+			}
+			matches = append(matches, bootstrapMatch{Name: b.Name, Line: truncate(line, 120)})
+			break
+		}
+	}
+	return matches
+}
+
+func bootstrapEvidence(matches []bootstrapMatch) string {
+	parts := make([]string, len(matches))
+	for i, m := range matches {
+		parts[i] = fmt.Sprintf("%s (%s)", m.Name, m.Line)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// userDataAssignment matches shell, env-file and YAML assignments such as
+// `export OPENAI_API_KEY="sk-..."` or `HF_TOKEN: hf_...`.
+var userDataAssignment = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\s*[=:]\s*["']?([^\s"';]+)`)
+
+// userDataAPIKeys returns the masked LLM provider keys in a script: values
+// assigned to variables named like provider keys, and anything shaped like
+// a provider key. Values that are expanded at boot, such as
+// $(aws secretsmanager ...), and values too short to be a real key, such as
+// placeholders, are skipped.
+func userDataAPIKeys(script string) []string {
+	var keys []string
+	add := func(key string) {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		var assigned []string
+		for _, m := range userDataAssignment.FindAllStringSubmatch(line, -1) {
+			name, value := m[1], m[2]
+			if !isAPIKeyEntry(name, value) {
+				continue
+			}
+			assigned = append(assigned, value)
+			add(maskAPIKey(name + "=" + value))
+		}
+		for _, key := range providerKey.FindAllString(line, -1) {
+			if !slices.ContainsFunc(assigned, func(v string) bool { return strings.Contains(v, key) }) {
+				add(maskSecret(key))
+			}
+		}
+	}
+	return keys
+}
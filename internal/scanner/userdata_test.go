@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/K0NGR3SS/ghostweights/internal/aws/fake"
+	"github.com/K0NGR3SS/ghostweights/internal/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func userData(script string) *string {
+	return aws.String(base64.StdEncoding.EncodeToString([]byte(script)))
+}
+
+func gzipUserData(script string) *string {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(script))
+	zw.Close()
+	return aws.String(base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+func TestScanUserData(t *testing.T) {
+	ec2 := &fake.EC2{
+		UserData: map[string]string{
+			"i-ollama": *gzipUserData(`#!/bin/bash
+curl -fsSL https://ollama.ai/install.sh | sh
+systemctl enable --now ollama
+export OPENAI_API_KEY="sk-proj-abcdefghijklmnopqrstuvwx"
+`),
+			"i-cloudinit": *userData(`#cloud-config
+runcmd:
+  - pip install vllm==0.6.3
+  - docker run -e HF_TOKEN=$(aws ssm get-parameter --name hf --query Parameter.Value) vllm/vllm-openai
+write_files:
+  - path: /etc/app.env
+    content: |
+      ANTHROPIC_API_KEY: sk-ant-REDACTED
+      OPENAI_BASE_URL=https://api.openai.com/v1
+`),
+			"i-web":    *userData("#!/bin/bash\nyum install -y nginx\n"),
+			"i-broken": "not base64!",
+		},
+	}
+	instances := []types.Instance{testInstance("i-ollama"), testInstance("i-cloudinit"), testInstance("i-web"), testInstance("i-broken"), testInstance("i-none")}
+
+	scn := newTestScanner(ec2, nil, nil)
+	findings, err := scn.ScanUserData(context.Background(), &Target{Region: "us-east-1", Instances: instances})
+	if err != nil {
+		t.Fatalf("ScanUserData: %v", err)
+	}
+
+	type key struct{ resource, service string }
+	got := map[key][]models.Finding{}
+	for _, f := range findings {
+		k := key{f.InstanceID, f.Service}
+		got[k] = append(got[k], f)
+	}
+
+	want := map[key]string{
+		{"i-ollama", "User Data AI Bootstrap"}:    "Ollama (curl -fsSL https://ollama.ai/install.sh | sh)",
+		{"i-ollama", "Exposed API Key"}:           "OPENAI_API_KEY=sk-p***uvwx",
+		{"i-cloudinit", "User Data AI Bootstrap"}: "vLLM (- pip install vllm==0.6.3)",
+		{"i-cloudinit", "Exposed API Key"}:        "ANTHROPIC_API_KEY=sk-a***stuv",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d finding kinds, want %d: %v", len(got), len(want), got)
+	}
+	for k, evidence := range want {
+		fs := got[k]
+		if len(fs) != 1 {
+			t.Errorf("%s / %s: got %d findings, want 1: %v", k.resource, k.service, len(fs), fs)
+			continue
+		}
+		if !strings.Contains(fs[0].Evidence, evidence) {
+			t.Errorf("%s / %s: evidence = %q, want it to contain %q", k.resource, k.service, fs[0].Evidence, evidence)
+		}
+	}
+	if ec2.Calls["DescribeInstanceAttribute"] != len(instances) {
+		t.Errorf("DescribeInstanceAttribute called %d times, want %d", ec2.Calls["DescribeInstanceAttribute"], len(instances))
+	}
+}
+
+func TestUserDataAPIKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "export",
+			script: "export HUGGINGFACE_TOKEN='hf_abcdefghijklmnopqrstuvwxyz0123456789'",
+			want:   []string{"HUGGINGFACE_TOKEN=hf_a***6789"},
+		},
+		{
+			name:   "bare provider key",
+			script: "curl -H 'Authorization: Bearer sk-ant-REDACTED' https://api.anthropic.com",
+			want:   []string{"sk-a***stuv"},
+		},
+		{
+			name:   "resolved at boot",
+			script: "OPENAI_API_KEY=$(aws secretsmanager get-secret-value --secret-id openai)",
+		},
+		{
+			name:   "commented out",
+			script: "# OPENAI_API_KEY=sk-abcdefghijklmnopqrstuvwxyz",
+		},
+		{
+			name:   "not a secret",
+			script: "OPENAI_BASE_URL=https://example.com/v1\nOPENAI_API_KEY=changeme",
+		},
+	}
+	for _, tt := range tests {
+		if got := userDataAPIKeys(tt.script); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: userDataAPIKeys = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeUserData(t *testing.T) {
+	script := "#!/bin/bash\necho hello\n"
+	for name, encoded := range map[string]*string{"plain": userData(script), "gzip": gzipUserData(script)} {
+		got, err := decodeUserData(*encoded)
+		if err != nil || got != script {
+			t.Errorf("%s: decodeUserData = %q, %v; want %q", name, got, err, script)
+		}
+	}
+	if _, err := decodeUserData("not base64!"); err == nil {
+		t.Error("decodeUserData accepted invalid base64")
+	}
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"fmt"
+
 	"github.com/K0NGR3SS/ghostweights/internal/models"
 	"github.com/pterm/pterm"
 )
@@ -54,6 +56,22 @@ func StartSpinner(text string) *pterm.SpinnerPrinter {
 	spinner, _ := pterm.DefaultSpinner.Start(text)
 	return spinner
 }
+
+// StartLabeledSpinner starts a spinner rendered as one line of multi, with
+// label shown next to the spinner frame on every update.
+func StartLabeledSpinner(multi *pterm.MultiPrinter, label, text string) *pterm.SpinnerPrinter {
+	sequence := make([]string, len(pterm.DefaultSpinner.Sequence))
+	for i, frame := range pterm.DefaultSpinner.Sequence {
+		sequence[i] = fmt.Sprintf("%s [%s]", frame, label)
+	}
+
+	spinner, _ := pterm.DefaultSpinner.
+		WithSequence(sequence...).
+		WithWriter(multi.NewWriter()).
+		Start(text)
+	return spinner
+}
+
 func UpdateSpinner(spinner *pterm.SpinnerPrinter, text string) {
     if spinner != nil {
         spinner.UpdateText(text)